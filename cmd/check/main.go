@@ -84,7 +84,11 @@ func checkPR(stdin []byte) {
 	if err != nil {
 		log.Fatalf("failed to create git manager: %v", err)
 	}
-	response, err := pr.Check(request, git)
+	github, err := models.NewGithubClient(request.Source.CommonConfig, request.Source.GithubConfig)
+	if err != nil {
+		log.Fatalf("failed to create github manager: %v", err)
+	}
+	response, err := pr.Check(request, github, git)
 	if err != nil {
 		log.Fatalf("check failed: %v", err)
 	}
@@ -53,7 +53,11 @@ func getPRList(stdin []byte, outputDir string) {
 	if err := request.Source.Validate(); err != nil {
 		log.Fatalf("invalid source configuration: %v", err)
 	}
-	response, err := prlist.Get(request, outputDir)
+	github, err := models.NewGithubClient(request.Source.CommonConfig, request.Source.GithubConfig)
+	if err != nil {
+		log.Fatalf("failed to create github manager: %v", err)
+	}
+	response, err := prlist.Get(request, github, outputDir)
 	if err != nil {
 		log.Fatalf("get failed: %v", err)
 	}
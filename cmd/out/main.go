@@ -6,9 +6,11 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 
 	models "github.com/cloudfoundry-community/github-pr-instances-resource/models"
 	"github.com/cloudfoundry-community/github-pr-instances-resource/pr"
+	"github.com/cloudfoundry-community/github-pr-instances-resource/prlist"
 )
 
 type Request struct {
@@ -34,12 +36,38 @@ func main() {
 	sourceDir := os.Args[1]
 
 	if request.Source.Number == 0 {
-		log.Fatalf("can only put when source.number is specified")
+		putPRList(stdin, sourceDir)
 	} else {
 		putPR(stdin, sourceDir)
 	}
 }
 
+func putPRList(stdin []byte, sourceDir string) {
+	decoder := json.NewDecoder(bytes.NewReader(stdin))
+	decoder.DisallowUnknownFields()
+
+	var request prlist.PutRequest
+	if err := decoder.Decode(&request); err != nil {
+		log.Fatalf("failed to unmarshal request: %s", err)
+	}
+
+	if err := request.Source.Validate(); err != nil {
+		log.Fatalf("invalid source configuration: %s", err)
+	}
+	github, err := models.NewGithubClient(request.Source.CommonConfig, request.Source.GithubConfig)
+	if err != nil {
+		log.Fatalf("failed to create github manager: %s", err)
+	}
+	response, err := prlist.Put(request, github, sourceDir)
+	if err != nil {
+		log.Fatalf("put failed: %s", err)
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(response); err != nil {
+		log.Fatalf("failed to marshal response: %s", err)
+	}
+}
+
 func putPR(stdin []byte, sourceDir string) {
 	decoder := json.NewDecoder(bytes.NewReader(stdin))
 	decoder.DisallowUnknownFields()
@@ -56,7 +84,19 @@ func putPR(stdin []byte, sourceDir string) {
 	if err != nil {
 		log.Fatalf("failed to create github manager: %s", err)
 	}
-	response, err := pr.Put(request, github, sourceDir)
+
+	// Backports are cherry-picked in the repository fetched by get, and changes
+	// are pushed from the repository given by the push parameters.
+	repository := filepath.Join(sourceDir, request.Params.Path)
+	if request.Params.Push != nil {
+		repository = filepath.Join(sourceDir, request.Params.Push.Repository)
+	}
+	git, err := models.NewGitClient(request.Source.CommonConfig, request.Source.DisableGitLFS, repository, os.Stderr)
+	if err != nil {
+		log.Fatalf("failed to create git manager: %v", err)
+	}
+
+	response, err := pr.Put(request, github, git, sourceDir)
 	if err != nil {
 		log.Fatalf("put failed: %s", err)
 	}
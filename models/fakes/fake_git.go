@@ -21,6 +21,37 @@ type FakeGit struct {
 	checkoutReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CherryPickStub        func(string, string, string, []string) ([]string, error)
+	cherryPickMutex       sync.RWMutex
+	cherryPickArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 []string
+	}
+	cherryPickReturns struct {
+		result1 []string
+		result2 error
+	}
+	cherryPickReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	CommitStub        func(string, string, string) (bool, error)
+	commitMutex       sync.RWMutex
+	commitArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	commitReturns struct {
+		result1 bool
+		result2 error
+	}
+	commitReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	FetchStub        func(string, int, int, bool, bool) error
 	fetchMutex       sync.RWMutex
 	fetchArgsForCall []struct {
@@ -85,6 +116,19 @@ type FakeGit struct {
 	pullReturnsOnCall map[int]struct {
 		result1 error
 	}
+	PushStub        func(string, string, string) error
+	pushMutex       sync.RWMutex
+	pushArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	pushReturns struct {
+		result1 error
+	}
+	pushReturnsOnCall map[int]struct {
+		result1 error
+	}
 	RebaseStub        func(string, string, bool) error
 	rebaseMutex       sync.RWMutex
 	rebaseArgsForCall []struct {
@@ -98,13 +142,14 @@ type FakeGit struct {
 	rebaseReturnsOnCall map[int]struct {
 		result1 error
 	}
-	RevListStub        func(*string, []string, []string, bool) ([]string, error)
+	RevListStub        func(*string, []string, []string, bool, bool) ([]string, error)
 	revListMutex       sync.RWMutex
 	revListArgsForCall []struct {
 		arg1 *string
 		arg2 []string
 		arg3 []string
 		arg4 bool
+		arg5 bool
 	}
 	revListReturns struct {
 		result1 []string
@@ -193,6 +238,142 @@ func (fake *FakeGit) CheckoutReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeGit) CherryPick(arg1 string, arg2 string, arg3 string, arg4 []string) ([]string, error) {
+	var arg4Copy []string
+	if arg4 != nil {
+		arg4Copy = make([]string, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.cherryPickMutex.Lock()
+	ret, specificReturn := fake.cherryPickReturnsOnCall[len(fake.cherryPickArgsForCall)]
+	fake.cherryPickArgsForCall = append(fake.cherryPickArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 []string
+	}{arg1, arg2, arg3, arg4Copy})
+	fake.recordInvocation("CherryPick", []interface{}{arg1, arg2, arg3, arg4Copy})
+	fake.cherryPickMutex.Unlock()
+	if fake.CherryPickStub != nil {
+		return fake.CherryPickStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.cherryPickReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGit) CherryPickCallCount() int {
+	fake.cherryPickMutex.RLock()
+	defer fake.cherryPickMutex.RUnlock()
+	return len(fake.cherryPickArgsForCall)
+}
+
+func (fake *FakeGit) CherryPickCalls(stub func(string, string, string, []string) ([]string, error)) {
+	fake.cherryPickMutex.Lock()
+	defer fake.cherryPickMutex.Unlock()
+	fake.CherryPickStub = stub
+}
+
+func (fake *FakeGit) CherryPickArgsForCall(i int) (string, string, string, []string) {
+	fake.cherryPickMutex.RLock()
+	defer fake.cherryPickMutex.RUnlock()
+	argsForCall := fake.cherryPickArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGit) CherryPickReturns(result1 []string, result2 error) {
+	fake.cherryPickMutex.Lock()
+	defer fake.cherryPickMutex.Unlock()
+	fake.CherryPickStub = nil
+	fake.cherryPickReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGit) CherryPickReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.cherryPickMutex.Lock()
+	defer fake.cherryPickMutex.Unlock()
+	fake.CherryPickStub = nil
+	if fake.cherryPickReturnsOnCall == nil {
+		fake.cherryPickReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.cherryPickReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGit) Commit(arg1 string, arg2 string, arg3 string) (bool, error) {
+	fake.commitMutex.Lock()
+	ret, specificReturn := fake.commitReturnsOnCall[len(fake.commitArgsForCall)]
+	fake.commitArgsForCall = append(fake.commitArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Commit", []interface{}{arg1, arg2, arg3})
+	fake.commitMutex.Unlock()
+	if fake.CommitStub != nil {
+		return fake.CommitStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.commitReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGit) CommitCallCount() int {
+	fake.commitMutex.RLock()
+	defer fake.commitMutex.RUnlock()
+	return len(fake.commitArgsForCall)
+}
+
+func (fake *FakeGit) CommitCalls(stub func(string, string, string) (bool, error)) {
+	fake.commitMutex.Lock()
+	defer fake.commitMutex.Unlock()
+	fake.CommitStub = stub
+}
+
+func (fake *FakeGit) CommitArgsForCall(i int) (string, string, string) {
+	fake.commitMutex.RLock()
+	defer fake.commitMutex.RUnlock()
+	argsForCall := fake.commitArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGit) CommitReturns(result1 bool, result2 error) {
+	fake.commitMutex.Lock()
+	defer fake.commitMutex.Unlock()
+	fake.CommitStub = nil
+	fake.commitReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGit) CommitReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.commitMutex.Lock()
+	defer fake.commitMutex.Unlock()
+	fake.CommitStub = nil
+	if fake.commitReturnsOnCall == nil {
+		fake.commitReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.commitReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeGit) Fetch(arg1 string, arg2 int, arg3 int, arg4 bool, arg5 bool) error {
 	fake.fetchMutex.Lock()
 	ret, specificReturn := fake.fetchReturnsOnCall[len(fake.fetchArgsForCall)]
@@ -502,6 +683,68 @@ func (fake *FakeGit) PullReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeGit) Push(arg1 string, arg2 string, arg3 string) error {
+	fake.pushMutex.Lock()
+	ret, specificReturn := fake.pushReturnsOnCall[len(fake.pushArgsForCall)]
+	fake.pushArgsForCall = append(fake.pushArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Push", []interface{}{arg1, arg2, arg3})
+	fake.pushMutex.Unlock()
+	if fake.PushStub != nil {
+		return fake.PushStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.pushReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGit) PushCallCount() int {
+	fake.pushMutex.RLock()
+	defer fake.pushMutex.RUnlock()
+	return len(fake.pushArgsForCall)
+}
+
+func (fake *FakeGit) PushCalls(stub func(string, string, string) error) {
+	fake.pushMutex.Lock()
+	defer fake.pushMutex.Unlock()
+	fake.PushStub = stub
+}
+
+func (fake *FakeGit) PushArgsForCall(i int) (string, string, string) {
+	fake.pushMutex.RLock()
+	defer fake.pushMutex.RUnlock()
+	argsForCall := fake.pushArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGit) PushReturns(result1 error) {
+	fake.pushMutex.Lock()
+	defer fake.pushMutex.Unlock()
+	fake.PushStub = nil
+	fake.pushReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGit) PushReturnsOnCall(i int, result1 error) {
+	fake.pushMutex.Lock()
+	defer fake.pushMutex.Unlock()
+	fake.PushStub = nil
+	if fake.pushReturnsOnCall == nil {
+		fake.pushReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pushReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeGit) Rebase(arg1 string, arg2 string, arg3 bool) error {
 	fake.rebaseMutex.Lock()
 	ret, specificReturn := fake.rebaseReturnsOnCall[len(fake.rebaseArgsForCall)]
@@ -564,7 +807,7 @@ func (fake *FakeGit) RebaseReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeGit) RevList(arg1 *string, arg2 []string, arg3 []string, arg4 bool) ([]string, error) {
+func (fake *FakeGit) RevList(arg1 *string, arg2 []string, arg3 []string, arg4 bool, arg5 bool) ([]string, error) {
 	var arg2Copy []string
 	if arg2 != nil {
 		arg2Copy = make([]string, len(arg2))
@@ -582,11 +825,12 @@ func (fake *FakeGit) RevList(arg1 *string, arg2 []string, arg3 []string, arg4 bo
 		arg2 []string
 		arg3 []string
 		arg4 bool
-	}{arg1, arg2Copy, arg3Copy, arg4})
-	fake.recordInvocation("RevList", []interface{}{arg1, arg2Copy, arg3Copy, arg4})
+		arg5 bool
+	}{arg1, arg2Copy, arg3Copy, arg4, arg5})
+	fake.recordInvocation("RevList", []interface{}{arg1, arg2Copy, arg3Copy, arg4, arg5})
 	fake.revListMutex.Unlock()
 	if fake.RevListStub != nil {
-		return fake.RevListStub(arg1, arg2, arg3, arg4)
+		return fake.RevListStub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -601,17 +845,17 @@ func (fake *FakeGit) RevListCallCount() int {
 	return len(fake.revListArgsForCall)
 }
 
-func (fake *FakeGit) RevListCalls(stub func(*string, []string, []string, bool) ([]string, error)) {
+func (fake *FakeGit) RevListCalls(stub func(*string, []string, []string, bool, bool) ([]string, error)) {
 	fake.revListMutex.Lock()
 	defer fake.revListMutex.Unlock()
 	fake.RevListStub = stub
 }
 
-func (fake *FakeGit) RevListArgsForCall(i int) (*string, []string, []string, bool) {
+func (fake *FakeGit) RevListArgsForCall(i int) (*string, []string, []string, bool, bool) {
 	fake.revListMutex.RLock()
 	defer fake.revListMutex.RUnlock()
 	argsForCall := fake.revListArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeGit) RevListReturns(result1 []string, result2 error) {
@@ -708,6 +952,10 @@ func (fake *FakeGit) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.checkoutMutex.RLock()
 	defer fake.checkoutMutex.RUnlock()
+	fake.cherryPickMutex.RLock()
+	defer fake.cherryPickMutex.RUnlock()
+	fake.commitMutex.RLock()
+	defer fake.commitMutex.RUnlock()
 	fake.fetchMutex.RLock()
 	defer fake.fetchMutex.RUnlock()
 	fake.gitCryptUnlockMutex.RLock()
@@ -718,6 +966,8 @@ func (fake *FakeGit) Invocations() map[string][][]interface{} {
 	defer fake.mergeMutex.RUnlock()
 	fake.pullMutex.RLock()
 	defer fake.pullMutex.RUnlock()
+	fake.pushMutex.RLock()
+	defer fake.pushMutex.RUnlock()
 	fake.rebaseMutex.RLock()
 	defer fake.rebaseMutex.RUnlock()
 	fake.revListMutex.RLock()
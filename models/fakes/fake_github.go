@@ -9,6 +9,124 @@ import (
 )
 
 type FakeGithub struct {
+	AddLabelsStub        func(int, []string) error
+	addLabelsMutex       sync.RWMutex
+	addLabelsArgsForCall []struct {
+		arg1 int
+		arg2 []string
+	}
+	addLabelsReturns struct {
+		result1 error
+	}
+	addLabelsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	BranchExistsStub        func(string) (bool, error)
+	branchExistsMutex       sync.RWMutex
+	branchExistsArgsForCall []struct {
+		arg1 string
+	}
+	branchExistsReturns struct {
+		result1 bool
+		result2 error
+	}
+	branchExistsReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	ClosePullRequestStub        func(string) error
+	closePullRequestMutex       sync.RWMutex
+	closePullRequestArgsForCall []struct {
+		arg1 string
+	}
+	closePullRequestReturns struct {
+		result1 error
+	}
+	closePullRequestReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ConvertPullRequestToDraftStub        func(string) error
+	convertPullRequestToDraftMutex       sync.RWMutex
+	convertPullRequestToDraftArgsForCall []struct {
+		arg1 string
+	}
+	convertPullRequestToDraftReturns struct {
+		result1 error
+	}
+	convertPullRequestToDraftReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CountCommitsBehindStub        func(string, string) (int, error)
+	countCommitsBehindMutex       sync.RWMutex
+	countCommitsBehindArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	countCommitsBehindReturns struct {
+		result1 int
+		result2 error
+	}
+	countCommitsBehindReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	CreateDeploymentStub        func(string, string, string) (int64, error)
+	createDeploymentMutex       sync.RWMutex
+	createDeploymentArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	createDeploymentReturns struct {
+		result1 int64
+		result2 error
+	}
+	createDeploymentReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	CreateDeploymentStatusStub        func(int64, string, string, string, string) error
+	createDeploymentStatusMutex       sync.RWMutex
+	createDeploymentStatusArgsForCall []struct {
+		arg1 int64
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+	}
+	createDeploymentStatusReturns struct {
+		result1 error
+	}
+	createDeploymentStatusReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreatePullRequestStub        func(string, string, string, string) (int, error)
+	createPullRequestMutex       sync.RWMutex
+	createPullRequestArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	createPullRequestReturns struct {
+		result1 int
+		result2 error
+	}
+	createPullRequestReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	DeleteBranchStub        func(string) error
+	deleteBranchMutex       sync.RWMutex
+	deleteBranchArgsForCall []struct {
+		arg1 string
+	}
+	deleteBranchReturns struct {
+		result1 error
+	}
+	deleteBranchReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DeletePreviousCommentsStub        func(int) error
 	deletePreviousCommentsMutex       sync.RWMutex
 	deletePreviousCommentsArgsForCall []struct {
@@ -20,6 +138,89 @@ type FakeGithub struct {
 	deletePreviousCommentsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DisablePullRequestAutoMergeStub        func(string) error
+	disablePullRequestAutoMergeMutex       sync.RWMutex
+	disablePullRequestAutoMergeArgsForCall []struct {
+		arg1 string
+	}
+	disablePullRequestAutoMergeReturns struct {
+		result1 error
+	}
+	disablePullRequestAutoMergeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DispatchRepositoryEventStub        func(string, map[string]string) error
+	dispatchRepositoryEventMutex       sync.RWMutex
+	dispatchRepositoryEventArgsForCall []struct {
+		arg1 string
+		arg2 map[string]string
+	}
+	dispatchRepositoryEventReturns struct {
+		result1 error
+	}
+	dispatchRepositoryEventReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DispatchWorkflowStub        func(string, string, map[string]string, string) (*models.WorkflowRun, error)
+	dispatchWorkflowMutex       sync.RWMutex
+	dispatchWorkflowArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 map[string]string
+		arg4 string
+	}
+	dispatchWorkflowReturns struct {
+		result1 *models.WorkflowRun
+		result2 error
+	}
+	dispatchWorkflowReturnsOnCall map[int]struct {
+		result1 *models.WorkflowRun
+		result2 error
+	}
+	EnablePullRequestAutoMergeStub        func(string, string, string, string, string) (*models.AutoMergeRequestObject, error)
+	enablePullRequestAutoMergeMutex       sync.RWMutex
+	enablePullRequestAutoMergeArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+	}
+	enablePullRequestAutoMergeReturns struct {
+		result1 *models.AutoMergeRequestObject
+		result2 error
+	}
+	enablePullRequestAutoMergeReturnsOnCall map[int]struct {
+		result1 *models.AutoMergeRequestObject
+		result2 error
+	}
+	FindPullRequestStub        func(string) (int, error)
+	findPullRequestMutex       sync.RWMutex
+	findPullRequestArgsForCall []struct {
+		arg1 string
+	}
+	findPullRequestReturns struct {
+		result1 int
+		result2 error
+	}
+	findPullRequestReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	GetOkToTestStub        func(int, string) (*models.OkToTest, error)
+	getOkToTestMutex       sync.RWMutex
+	getOkToTestArgsForCall []struct {
+		arg1 int
+		arg2 string
+	}
+	getOkToTestReturns struct {
+		result1 *models.OkToTest
+		result2 error
+	}
+	getOkToTestReturnsOnCall map[int]struct {
+		result1 *models.OkToTest
+		result2 error
+	}
 	GetPullRequestStub        func(int, string) (*models.PullRequest, error)
 	getPullRequestMutex       sync.RWMutex
 	getPullRequestArgsForCall []struct {
@@ -34,6 +235,59 @@ type FakeGithub struct {
 		result1 *models.PullRequest
 		result2 error
 	}
+	GetPullRequestBodyStub        func(int) (string, error)
+	getPullRequestBodyMutex       sync.RWMutex
+	getPullRequestBodyArgsForCall []struct {
+		arg1 int
+	}
+	getPullRequestBodyReturns struct {
+		result1 string
+		result2 error
+	}
+	getPullRequestBodyReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetPullRequestHeadSHAStub        func(int) (string, error)
+	getPullRequestHeadSHAMutex       sync.RWMutex
+	getPullRequestHeadSHAArgsForCall []struct {
+		arg1 int
+	}
+	getPullRequestHeadSHAReturns struct {
+		result1 string
+		result2 error
+	}
+	getPullRequestHeadSHAReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	ListCommitChecksStub        func(string) ([]models.CommitCheck, error)
+	listCommitChecksMutex       sync.RWMutex
+	listCommitChecksArgsForCall []struct {
+		arg1 string
+	}
+	listCommitChecksReturns struct {
+		result1 []models.CommitCheck
+		result2 error
+	}
+	listCommitChecksReturnsOnCall map[int]struct {
+		result1 []models.CommitCheck
+		result2 error
+	}
+	ListDeploymentsStub        func(string, string) ([]int64, error)
+	listDeploymentsMutex       sync.RWMutex
+	listDeploymentsArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	listDeploymentsReturns struct {
+		result1 []int64
+		result2 error
+	}
+	listDeploymentsReturnsOnCall map[int]struct {
+		result1 []int64
+		result2 error
+	}
 	ListModifiedFilesStub        func(int) ([]string, error)
 	listModifiedFilesMutex       sync.RWMutex
 	listModifiedFilesArgsForCall []struct {
@@ -47,6 +301,32 @@ type FakeGithub struct {
 		result1 []string
 		result2 error
 	}
+	ListPullRequestCommitsStub        func(int) ([]models.CommitObject, error)
+	listPullRequestCommitsMutex       sync.RWMutex
+	listPullRequestCommitsArgsForCall []struct {
+		arg1 int
+	}
+	listPullRequestCommitsReturns struct {
+		result1 []models.CommitObject
+		result2 error
+	}
+	listPullRequestCommitsReturnsOnCall map[int]struct {
+		result1 []models.CommitObject
+		result2 error
+	}
+	ListPullRequestLabelsStub        func(int) ([]models.LabelObject, error)
+	listPullRequestLabelsMutex       sync.RWMutex
+	listPullRequestLabelsArgsForCall []struct {
+		arg1 int
+	}
+	listPullRequestLabelsReturns struct {
+		result1 []models.LabelObject
+		result2 error
+	}
+	listPullRequestLabelsReturnsOnCall map[int]struct {
+		result1 []models.LabelObject
+		result2 error
+	}
 	ListPullRequestsStub        func([]githubv4.PullRequestState) ([]*models.PullRequest, error)
 	listPullRequestsMutex       sync.RWMutex
 	listPullRequestsArgsForCall []struct {
@@ -60,6 +340,34 @@ type FakeGithub struct {
 		result1 []*models.PullRequest
 		result2 error
 	}
+	MarkPullRequestReadyForReviewStub        func(string) error
+	markPullRequestReadyForReviewMutex       sync.RWMutex
+	markPullRequestReadyForReviewArgsForCall []struct {
+		arg1 string
+	}
+	markPullRequestReadyForReviewReturns struct {
+		result1 error
+	}
+	markPullRequestReadyForReviewReturnsOnCall map[int]struct {
+		result1 error
+	}
+	MergePullRequestStub        func(int, string, string, string, string) (string, error)
+	mergePullRequestMutex       sync.RWMutex
+	mergePullRequestArgsForCall []struct {
+		arg1 int
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+	}
+	mergePullRequestReturns struct {
+		result1 string
+		result2 error
+	}
+	mergePullRequestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	PostCommentStub        func(int, string) error
 	postCommentMutex       sync.RWMutex
 	postCommentArgsForCall []struct {
@@ -72,6 +380,29 @@ type FakeGithub struct {
 	postCommentReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RemoveLabelStub        func(int, string) error
+	removeLabelMutex       sync.RWMutex
+	removeLabelArgsForCall []struct {
+		arg1 int
+		arg2 string
+	}
+	removeLabelReturns struct {
+		result1 error
+	}
+	removeLabelReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReopenPullRequestStub        func(string) error
+	reopenPullRequestMutex       sync.RWMutex
+	reopenPullRequestArgsForCall []struct {
+		arg1 string
+	}
+	reopenPullRequestReturns struct {
+		result1 error
+	}
+	reopenPullRequestReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UpdateCommitStatusStub        func(string, string, string, string, string, string) error
 	updateCommitStatusMutex       sync.RWMutex
 	updateCommitStatusArgsForCall []struct {
@@ -88,103 +419,1092 @@ type FakeGithub struct {
 	updateCommitStatusReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdatePullRequestBodyStub        func(int, string) error
+	updatePullRequestBodyMutex       sync.RWMutex
+	updatePullRequestBodyArgsForCall []struct {
+		arg1 int
+		arg2 string
+	}
+	updatePullRequestBodyReturns struct {
+		result1 error
+	}
+	updatePullRequestBodyReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdatePullRequestBranchStub        func(string, string, string) (string, error)
+	updatePullRequestBranchMutex       sync.RWMutex
+	updatePullRequestBranchArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	updatePullRequestBranchReturns struct {
+		result1 string
+		result2 error
+	}
+	updatePullRequestBranchReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	UpsertCommentStub        func(int, string, string) error
+	upsertCommentMutex       sync.RWMutex
+	upsertCommentArgsForCall []struct {
+		arg1 int
+		arg2 string
+		arg3 string
+	}
+	upsertCommentReturns struct {
+		result1 error
+	}
+	upsertCommentReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeGithub) DeletePreviousComments(arg1 int) error {
-	fake.deletePreviousCommentsMutex.Lock()
-	ret, specificReturn := fake.deletePreviousCommentsReturnsOnCall[len(fake.deletePreviousCommentsArgsForCall)]
-	fake.deletePreviousCommentsArgsForCall = append(fake.deletePreviousCommentsArgsForCall, struct {
+func (fake *FakeGithub) AddLabels(arg1 int, arg2 []string) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.addLabelsMutex.Lock()
+	ret, specificReturn := fake.addLabelsReturnsOnCall[len(fake.addLabelsArgsForCall)]
+	fake.addLabelsArgsForCall = append(fake.addLabelsArgsForCall, struct {
 		arg1 int
-	}{arg1})
-	fake.recordInvocation("DeletePreviousComments", []interface{}{arg1})
-	fake.deletePreviousCommentsMutex.Unlock()
-	if fake.DeletePreviousCommentsStub != nil {
-		return fake.DeletePreviousCommentsStub(arg1)
+		arg2 []string
+	}{arg1, arg2Copy})
+	fake.recordInvocation("AddLabels", []interface{}{arg1, arg2Copy})
+	fake.addLabelsMutex.Unlock()
+	if fake.AddLabelsStub != nil {
+		return fake.AddLabelsStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.deletePreviousCommentsReturns
+	fakeReturns := fake.addLabelsReturns
 	return fakeReturns.result1
 }
 
-func (fake *FakeGithub) DeletePreviousCommentsCallCount() int {
-	fake.deletePreviousCommentsMutex.RLock()
-	defer fake.deletePreviousCommentsMutex.RUnlock()
-	return len(fake.deletePreviousCommentsArgsForCall)
+func (fake *FakeGithub) AddLabelsCallCount() int {
+	fake.addLabelsMutex.RLock()
+	defer fake.addLabelsMutex.RUnlock()
+	return len(fake.addLabelsArgsForCall)
 }
 
-func (fake *FakeGithub) DeletePreviousCommentsCalls(stub func(int) error) {
-	fake.deletePreviousCommentsMutex.Lock()
-	defer fake.deletePreviousCommentsMutex.Unlock()
-	fake.DeletePreviousCommentsStub = stub
+func (fake *FakeGithub) AddLabelsCalls(stub func(int, []string) error) {
+	fake.addLabelsMutex.Lock()
+	defer fake.addLabelsMutex.Unlock()
+	fake.AddLabelsStub = stub
 }
 
-func (fake *FakeGithub) DeletePreviousCommentsArgsForCall(i int) int {
-	fake.deletePreviousCommentsMutex.RLock()
-	defer fake.deletePreviousCommentsMutex.RUnlock()
-	argsForCall := fake.deletePreviousCommentsArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeGithub) AddLabelsArgsForCall(i int) (int, []string) {
+	fake.addLabelsMutex.RLock()
+	defer fake.addLabelsMutex.RUnlock()
+	argsForCall := fake.addLabelsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeGithub) DeletePreviousCommentsReturns(result1 error) {
-	fake.deletePreviousCommentsMutex.Lock()
-	defer fake.deletePreviousCommentsMutex.Unlock()
-	fake.DeletePreviousCommentsStub = nil
-	fake.deletePreviousCommentsReturns = struct {
+func (fake *FakeGithub) AddLabelsReturns(result1 error) {
+	fake.addLabelsMutex.Lock()
+	defer fake.addLabelsMutex.Unlock()
+	fake.AddLabelsStub = nil
+	fake.addLabelsReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeGithub) DeletePreviousCommentsReturnsOnCall(i int, result1 error) {
-	fake.deletePreviousCommentsMutex.Lock()
-	defer fake.deletePreviousCommentsMutex.Unlock()
-	fake.DeletePreviousCommentsStub = nil
-	if fake.deletePreviousCommentsReturnsOnCall == nil {
-		fake.deletePreviousCommentsReturnsOnCall = make(map[int]struct {
+func (fake *FakeGithub) AddLabelsReturnsOnCall(i int, result1 error) {
+	fake.addLabelsMutex.Lock()
+	defer fake.addLabelsMutex.Unlock()
+	fake.AddLabelsStub = nil
+	if fake.addLabelsReturnsOnCall == nil {
+		fake.addLabelsReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.deletePreviousCommentsReturnsOnCall[i] = struct {
+	fake.addLabelsReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeGithub) GetPullRequest(arg1 int, arg2 string) (*models.PullRequest, error) {
-	fake.getPullRequestMutex.Lock()
-	ret, specificReturn := fake.getPullRequestReturnsOnCall[len(fake.getPullRequestArgsForCall)]
-	fake.getPullRequestArgsForCall = append(fake.getPullRequestArgsForCall, struct {
-		arg1 int
-		arg2 string
-	}{arg1, arg2})
-	fake.recordInvocation("GetPullRequest", []interface{}{arg1, arg2})
-	fake.getPullRequestMutex.Unlock()
-	if fake.GetPullRequestStub != nil {
-		return fake.GetPullRequestStub(arg1, arg2)
+func (fake *FakeGithub) BranchExists(arg1 string) (bool, error) {
+	fake.branchExistsMutex.Lock()
+	ret, specificReturn := fake.branchExistsReturnsOnCall[len(fake.branchExistsArgsForCall)]
+	fake.branchExistsArgsForCall = append(fake.branchExistsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("BranchExists", []interface{}{arg1})
+	fake.branchExistsMutex.Unlock()
+	if fake.BranchExistsStub != nil {
+		return fake.BranchExistsStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getPullRequestReturns
+	fakeReturns := fake.branchExistsReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeGithub) GetPullRequestCallCount() int {
-	fake.getPullRequestMutex.RLock()
-	defer fake.getPullRequestMutex.RUnlock()
-	return len(fake.getPullRequestArgsForCall)
+func (fake *FakeGithub) BranchExistsCallCount() int {
+	fake.branchExistsMutex.RLock()
+	defer fake.branchExistsMutex.RUnlock()
+	return len(fake.branchExistsArgsForCall)
 }
 
-func (fake *FakeGithub) GetPullRequestCalls(stub func(int, string) (*models.PullRequest, error)) {
-	fake.getPullRequestMutex.Lock()
-	defer fake.getPullRequestMutex.Unlock()
-	fake.GetPullRequestStub = stub
+func (fake *FakeGithub) BranchExistsCalls(stub func(string) (bool, error)) {
+	fake.branchExistsMutex.Lock()
+	defer fake.branchExistsMutex.Unlock()
+	fake.BranchExistsStub = stub
 }
 
-func (fake *FakeGithub) GetPullRequestArgsForCall(i int) (int, string) {
-	fake.getPullRequestMutex.RLock()
+func (fake *FakeGithub) BranchExistsArgsForCall(i int) string {
+	fake.branchExistsMutex.RLock()
+	defer fake.branchExistsMutex.RUnlock()
+	argsForCall := fake.branchExistsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) BranchExistsReturns(result1 bool, result2 error) {
+	fake.branchExistsMutex.Lock()
+	defer fake.branchExistsMutex.Unlock()
+	fake.BranchExistsStub = nil
+	fake.branchExistsReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) BranchExistsReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.branchExistsMutex.Lock()
+	defer fake.branchExistsMutex.Unlock()
+	fake.BranchExistsStub = nil
+	if fake.branchExistsReturnsOnCall == nil {
+		fake.branchExistsReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.branchExistsReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ClosePullRequest(arg1 string) error {
+	fake.closePullRequestMutex.Lock()
+	ret, specificReturn := fake.closePullRequestReturnsOnCall[len(fake.closePullRequestArgsForCall)]
+	fake.closePullRequestArgsForCall = append(fake.closePullRequestArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ClosePullRequest", []interface{}{arg1})
+	fake.closePullRequestMutex.Unlock()
+	if fake.ClosePullRequestStub != nil {
+		return fake.ClosePullRequestStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.closePullRequestReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) ClosePullRequestCallCount() int {
+	fake.closePullRequestMutex.RLock()
+	defer fake.closePullRequestMutex.RUnlock()
+	return len(fake.closePullRequestArgsForCall)
+}
+
+func (fake *FakeGithub) ClosePullRequestCalls(stub func(string) error) {
+	fake.closePullRequestMutex.Lock()
+	defer fake.closePullRequestMutex.Unlock()
+	fake.ClosePullRequestStub = stub
+}
+
+func (fake *FakeGithub) ClosePullRequestArgsForCall(i int) string {
+	fake.closePullRequestMutex.RLock()
+	defer fake.closePullRequestMutex.RUnlock()
+	argsForCall := fake.closePullRequestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) ClosePullRequestReturns(result1 error) {
+	fake.closePullRequestMutex.Lock()
+	defer fake.closePullRequestMutex.Unlock()
+	fake.ClosePullRequestStub = nil
+	fake.closePullRequestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) ClosePullRequestReturnsOnCall(i int, result1 error) {
+	fake.closePullRequestMutex.Lock()
+	defer fake.closePullRequestMutex.Unlock()
+	fake.ClosePullRequestStub = nil
+	if fake.closePullRequestReturnsOnCall == nil {
+		fake.closePullRequestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closePullRequestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) ConvertPullRequestToDraft(arg1 string) error {
+	fake.convertPullRequestToDraftMutex.Lock()
+	ret, specificReturn := fake.convertPullRequestToDraftReturnsOnCall[len(fake.convertPullRequestToDraftArgsForCall)]
+	fake.convertPullRequestToDraftArgsForCall = append(fake.convertPullRequestToDraftArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ConvertPullRequestToDraft", []interface{}{arg1})
+	fake.convertPullRequestToDraftMutex.Unlock()
+	if fake.ConvertPullRequestToDraftStub != nil {
+		return fake.ConvertPullRequestToDraftStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.convertPullRequestToDraftReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) ConvertPullRequestToDraftCallCount() int {
+	fake.convertPullRequestToDraftMutex.RLock()
+	defer fake.convertPullRequestToDraftMutex.RUnlock()
+	return len(fake.convertPullRequestToDraftArgsForCall)
+}
+
+func (fake *FakeGithub) ConvertPullRequestToDraftCalls(stub func(string) error) {
+	fake.convertPullRequestToDraftMutex.Lock()
+	defer fake.convertPullRequestToDraftMutex.Unlock()
+	fake.ConvertPullRequestToDraftStub = stub
+}
+
+func (fake *FakeGithub) ConvertPullRequestToDraftArgsForCall(i int) string {
+	fake.convertPullRequestToDraftMutex.RLock()
+	defer fake.convertPullRequestToDraftMutex.RUnlock()
+	argsForCall := fake.convertPullRequestToDraftArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) ConvertPullRequestToDraftReturns(result1 error) {
+	fake.convertPullRequestToDraftMutex.Lock()
+	defer fake.convertPullRequestToDraftMutex.Unlock()
+	fake.ConvertPullRequestToDraftStub = nil
+	fake.convertPullRequestToDraftReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) ConvertPullRequestToDraftReturnsOnCall(i int, result1 error) {
+	fake.convertPullRequestToDraftMutex.Lock()
+	defer fake.convertPullRequestToDraftMutex.Unlock()
+	fake.ConvertPullRequestToDraftStub = nil
+	if fake.convertPullRequestToDraftReturnsOnCall == nil {
+		fake.convertPullRequestToDraftReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.convertPullRequestToDraftReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CountCommitsBehind(arg1 string, arg2 string) (int, error) {
+	fake.countCommitsBehindMutex.Lock()
+	ret, specificReturn := fake.countCommitsBehindReturnsOnCall[len(fake.countCommitsBehindArgsForCall)]
+	fake.countCommitsBehindArgsForCall = append(fake.countCommitsBehindArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("CountCommitsBehind", []interface{}{arg1, arg2})
+	fake.countCommitsBehindMutex.Unlock()
+	if fake.CountCommitsBehindStub != nil {
+		return fake.CountCommitsBehindStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.countCommitsBehindReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) CountCommitsBehindCallCount() int {
+	fake.countCommitsBehindMutex.RLock()
+	defer fake.countCommitsBehindMutex.RUnlock()
+	return len(fake.countCommitsBehindArgsForCall)
+}
+
+func (fake *FakeGithub) CountCommitsBehindCalls(stub func(string, string) (int, error)) {
+	fake.countCommitsBehindMutex.Lock()
+	defer fake.countCommitsBehindMutex.Unlock()
+	fake.CountCommitsBehindStub = stub
+}
+
+func (fake *FakeGithub) CountCommitsBehindArgsForCall(i int) (string, string) {
+	fake.countCommitsBehindMutex.RLock()
+	defer fake.countCommitsBehindMutex.RUnlock()
+	argsForCall := fake.countCommitsBehindArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) CountCommitsBehindReturns(result1 int, result2 error) {
+	fake.countCommitsBehindMutex.Lock()
+	defer fake.countCommitsBehindMutex.Unlock()
+	fake.CountCommitsBehindStub = nil
+	fake.countCommitsBehindReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) CountCommitsBehindReturnsOnCall(i int, result1 int, result2 error) {
+	fake.countCommitsBehindMutex.Lock()
+	defer fake.countCommitsBehindMutex.Unlock()
+	fake.CountCommitsBehindStub = nil
+	if fake.countCommitsBehindReturnsOnCall == nil {
+		fake.countCommitsBehindReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.countCommitsBehindReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) CreateDeployment(arg1 string, arg2 string, arg3 string) (int64, error) {
+	fake.createDeploymentMutex.Lock()
+	ret, specificReturn := fake.createDeploymentReturnsOnCall[len(fake.createDeploymentArgsForCall)]
+	fake.createDeploymentArgsForCall = append(fake.createDeploymentArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("CreateDeployment", []interface{}{arg1, arg2, arg3})
+	fake.createDeploymentMutex.Unlock()
+	if fake.CreateDeploymentStub != nil {
+		return fake.CreateDeploymentStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.createDeploymentReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) CreateDeploymentCallCount() int {
+	fake.createDeploymentMutex.RLock()
+	defer fake.createDeploymentMutex.RUnlock()
+	return len(fake.createDeploymentArgsForCall)
+}
+
+func (fake *FakeGithub) CreateDeploymentCalls(stub func(string, string, string) (int64, error)) {
+	fake.createDeploymentMutex.Lock()
+	defer fake.createDeploymentMutex.Unlock()
+	fake.CreateDeploymentStub = stub
+}
+
+func (fake *FakeGithub) CreateDeploymentArgsForCall(i int) (string, string, string) {
+	fake.createDeploymentMutex.RLock()
+	defer fake.createDeploymentMutex.RUnlock()
+	argsForCall := fake.createDeploymentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGithub) CreateDeploymentReturns(result1 int64, result2 error) {
+	fake.createDeploymentMutex.Lock()
+	defer fake.createDeploymentMutex.Unlock()
+	fake.CreateDeploymentStub = nil
+	fake.createDeploymentReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) CreateDeploymentReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.createDeploymentMutex.Lock()
+	defer fake.createDeploymentMutex.Unlock()
+	fake.CreateDeploymentStub = nil
+	if fake.createDeploymentReturnsOnCall == nil {
+		fake.createDeploymentReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.createDeploymentReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) CreateDeploymentStatus(arg1 int64, arg2 string, arg3 string, arg4 string, arg5 string) error {
+	fake.createDeploymentStatusMutex.Lock()
+	ret, specificReturn := fake.createDeploymentStatusReturnsOnCall[len(fake.createDeploymentStatusArgsForCall)]
+	fake.createDeploymentStatusArgsForCall = append(fake.createDeploymentStatusArgsForCall, struct {
+		arg1 int64
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("CreateDeploymentStatus", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.createDeploymentStatusMutex.Unlock()
+	if fake.CreateDeploymentStatusStub != nil {
+		return fake.CreateDeploymentStatusStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createDeploymentStatusReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) CreateDeploymentStatusCallCount() int {
+	fake.createDeploymentStatusMutex.RLock()
+	defer fake.createDeploymentStatusMutex.RUnlock()
+	return len(fake.createDeploymentStatusArgsForCall)
+}
+
+func (fake *FakeGithub) CreateDeploymentStatusCalls(stub func(int64, string, string, string, string) error) {
+	fake.createDeploymentStatusMutex.Lock()
+	defer fake.createDeploymentStatusMutex.Unlock()
+	fake.CreateDeploymentStatusStub = stub
+}
+
+func (fake *FakeGithub) CreateDeploymentStatusArgsForCall(i int) (int64, string, string, string, string) {
+	fake.createDeploymentStatusMutex.RLock()
+	defer fake.createDeploymentStatusMutex.RUnlock()
+	argsForCall := fake.createDeploymentStatusArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeGithub) CreateDeploymentStatusReturns(result1 error) {
+	fake.createDeploymentStatusMutex.Lock()
+	defer fake.createDeploymentStatusMutex.Unlock()
+	fake.CreateDeploymentStatusStub = nil
+	fake.createDeploymentStatusReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CreateDeploymentStatusReturnsOnCall(i int, result1 error) {
+	fake.createDeploymentStatusMutex.Lock()
+	defer fake.createDeploymentStatusMutex.Unlock()
+	fake.CreateDeploymentStatusStub = nil
+	if fake.createDeploymentStatusReturnsOnCall == nil {
+		fake.createDeploymentStatusReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createDeploymentStatusReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) CreatePullRequest(arg1 string, arg2 string, arg3 string, arg4 string) (int, error) {
+	fake.createPullRequestMutex.Lock()
+	ret, specificReturn := fake.createPullRequestReturnsOnCall[len(fake.createPullRequestArgsForCall)]
+	fake.createPullRequestArgsForCall = append(fake.createPullRequestArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("CreatePullRequest", []interface{}{arg1, arg2, arg3, arg4})
+	fake.createPullRequestMutex.Unlock()
+	if fake.CreatePullRequestStub != nil {
+		return fake.CreatePullRequestStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.createPullRequestReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) CreatePullRequestCallCount() int {
+	fake.createPullRequestMutex.RLock()
+	defer fake.createPullRequestMutex.RUnlock()
+	return len(fake.createPullRequestArgsForCall)
+}
+
+func (fake *FakeGithub) CreatePullRequestCalls(stub func(string, string, string, string) (int, error)) {
+	fake.createPullRequestMutex.Lock()
+	defer fake.createPullRequestMutex.Unlock()
+	fake.CreatePullRequestStub = stub
+}
+
+func (fake *FakeGithub) CreatePullRequestArgsForCall(i int) (string, string, string, string) {
+	fake.createPullRequestMutex.RLock()
+	defer fake.createPullRequestMutex.RUnlock()
+	argsForCall := fake.createPullRequestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGithub) CreatePullRequestReturns(result1 int, result2 error) {
+	fake.createPullRequestMutex.Lock()
+	defer fake.createPullRequestMutex.Unlock()
+	fake.CreatePullRequestStub = nil
+	fake.createPullRequestReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) CreatePullRequestReturnsOnCall(i int, result1 int, result2 error) {
+	fake.createPullRequestMutex.Lock()
+	defer fake.createPullRequestMutex.Unlock()
+	fake.CreatePullRequestStub = nil
+	if fake.createPullRequestReturnsOnCall == nil {
+		fake.createPullRequestReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.createPullRequestReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) DeleteBranch(arg1 string) error {
+	fake.deleteBranchMutex.Lock()
+	ret, specificReturn := fake.deleteBranchReturnsOnCall[len(fake.deleteBranchArgsForCall)]
+	fake.deleteBranchArgsForCall = append(fake.deleteBranchArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteBranch", []interface{}{arg1})
+	fake.deleteBranchMutex.Unlock()
+	if fake.DeleteBranchStub != nil {
+		return fake.DeleteBranchStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteBranchReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) DeleteBranchCallCount() int {
+	fake.deleteBranchMutex.RLock()
+	defer fake.deleteBranchMutex.RUnlock()
+	return len(fake.deleteBranchArgsForCall)
+}
+
+func (fake *FakeGithub) DeleteBranchCalls(stub func(string) error) {
+	fake.deleteBranchMutex.Lock()
+	defer fake.deleteBranchMutex.Unlock()
+	fake.DeleteBranchStub = stub
+}
+
+func (fake *FakeGithub) DeleteBranchArgsForCall(i int) string {
+	fake.deleteBranchMutex.RLock()
+	defer fake.deleteBranchMutex.RUnlock()
+	argsForCall := fake.deleteBranchArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) DeleteBranchReturns(result1 error) {
+	fake.deleteBranchMutex.Lock()
+	defer fake.deleteBranchMutex.Unlock()
+	fake.DeleteBranchStub = nil
+	fake.deleteBranchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DeleteBranchReturnsOnCall(i int, result1 error) {
+	fake.deleteBranchMutex.Lock()
+	defer fake.deleteBranchMutex.Unlock()
+	fake.DeleteBranchStub = nil
+	if fake.deleteBranchReturnsOnCall == nil {
+		fake.deleteBranchReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteBranchReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DeletePreviousComments(arg1 int) error {
+	fake.deletePreviousCommentsMutex.Lock()
+	ret, specificReturn := fake.deletePreviousCommentsReturnsOnCall[len(fake.deletePreviousCommentsArgsForCall)]
+	fake.deletePreviousCommentsArgsForCall = append(fake.deletePreviousCommentsArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("DeletePreviousComments", []interface{}{arg1})
+	fake.deletePreviousCommentsMutex.Unlock()
+	if fake.DeletePreviousCommentsStub != nil {
+		return fake.DeletePreviousCommentsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deletePreviousCommentsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) DeletePreviousCommentsCallCount() int {
+	fake.deletePreviousCommentsMutex.RLock()
+	defer fake.deletePreviousCommentsMutex.RUnlock()
+	return len(fake.deletePreviousCommentsArgsForCall)
+}
+
+func (fake *FakeGithub) DeletePreviousCommentsCalls(stub func(int) error) {
+	fake.deletePreviousCommentsMutex.Lock()
+	defer fake.deletePreviousCommentsMutex.Unlock()
+	fake.DeletePreviousCommentsStub = stub
+}
+
+func (fake *FakeGithub) DeletePreviousCommentsArgsForCall(i int) int {
+	fake.deletePreviousCommentsMutex.RLock()
+	defer fake.deletePreviousCommentsMutex.RUnlock()
+	argsForCall := fake.deletePreviousCommentsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) DeletePreviousCommentsReturns(result1 error) {
+	fake.deletePreviousCommentsMutex.Lock()
+	defer fake.deletePreviousCommentsMutex.Unlock()
+	fake.DeletePreviousCommentsStub = nil
+	fake.deletePreviousCommentsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DeletePreviousCommentsReturnsOnCall(i int, result1 error) {
+	fake.deletePreviousCommentsMutex.Lock()
+	defer fake.deletePreviousCommentsMutex.Unlock()
+	fake.DeletePreviousCommentsStub = nil
+	if fake.deletePreviousCommentsReturnsOnCall == nil {
+		fake.deletePreviousCommentsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deletePreviousCommentsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DisablePullRequestAutoMerge(arg1 string) error {
+	fake.disablePullRequestAutoMergeMutex.Lock()
+	ret, specificReturn := fake.disablePullRequestAutoMergeReturnsOnCall[len(fake.disablePullRequestAutoMergeArgsForCall)]
+	fake.disablePullRequestAutoMergeArgsForCall = append(fake.disablePullRequestAutoMergeArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DisablePullRequestAutoMerge", []interface{}{arg1})
+	fake.disablePullRequestAutoMergeMutex.Unlock()
+	if fake.DisablePullRequestAutoMergeStub != nil {
+		return fake.DisablePullRequestAutoMergeStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.disablePullRequestAutoMergeReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) DisablePullRequestAutoMergeCallCount() int {
+	fake.disablePullRequestAutoMergeMutex.RLock()
+	defer fake.disablePullRequestAutoMergeMutex.RUnlock()
+	return len(fake.disablePullRequestAutoMergeArgsForCall)
+}
+
+func (fake *FakeGithub) DisablePullRequestAutoMergeCalls(stub func(string) error) {
+	fake.disablePullRequestAutoMergeMutex.Lock()
+	defer fake.disablePullRequestAutoMergeMutex.Unlock()
+	fake.DisablePullRequestAutoMergeStub = stub
+}
+
+func (fake *FakeGithub) DisablePullRequestAutoMergeArgsForCall(i int) string {
+	fake.disablePullRequestAutoMergeMutex.RLock()
+	defer fake.disablePullRequestAutoMergeMutex.RUnlock()
+	argsForCall := fake.disablePullRequestAutoMergeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) DisablePullRequestAutoMergeReturns(result1 error) {
+	fake.disablePullRequestAutoMergeMutex.Lock()
+	defer fake.disablePullRequestAutoMergeMutex.Unlock()
+	fake.DisablePullRequestAutoMergeStub = nil
+	fake.disablePullRequestAutoMergeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DisablePullRequestAutoMergeReturnsOnCall(i int, result1 error) {
+	fake.disablePullRequestAutoMergeMutex.Lock()
+	defer fake.disablePullRequestAutoMergeMutex.Unlock()
+	fake.DisablePullRequestAutoMergeStub = nil
+	if fake.disablePullRequestAutoMergeReturnsOnCall == nil {
+		fake.disablePullRequestAutoMergeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.disablePullRequestAutoMergeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DispatchRepositoryEvent(arg1 string, arg2 map[string]string) error {
+	fake.dispatchRepositoryEventMutex.Lock()
+	ret, specificReturn := fake.dispatchRepositoryEventReturnsOnCall[len(fake.dispatchRepositoryEventArgsForCall)]
+	fake.dispatchRepositoryEventArgsForCall = append(fake.dispatchRepositoryEventArgsForCall, struct {
+		arg1 string
+		arg2 map[string]string
+	}{arg1, arg2})
+	fake.recordInvocation("DispatchRepositoryEvent", []interface{}{arg1, arg2})
+	fake.dispatchRepositoryEventMutex.Unlock()
+	if fake.DispatchRepositoryEventStub != nil {
+		return fake.DispatchRepositoryEventStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.dispatchRepositoryEventReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) DispatchRepositoryEventCallCount() int {
+	fake.dispatchRepositoryEventMutex.RLock()
+	defer fake.dispatchRepositoryEventMutex.RUnlock()
+	return len(fake.dispatchRepositoryEventArgsForCall)
+}
+
+func (fake *FakeGithub) DispatchRepositoryEventCalls(stub func(string, map[string]string) error) {
+	fake.dispatchRepositoryEventMutex.Lock()
+	defer fake.dispatchRepositoryEventMutex.Unlock()
+	fake.DispatchRepositoryEventStub = stub
+}
+
+func (fake *FakeGithub) DispatchRepositoryEventArgsForCall(i int) (string, map[string]string) {
+	fake.dispatchRepositoryEventMutex.RLock()
+	defer fake.dispatchRepositoryEventMutex.RUnlock()
+	argsForCall := fake.dispatchRepositoryEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) DispatchRepositoryEventReturns(result1 error) {
+	fake.dispatchRepositoryEventMutex.Lock()
+	defer fake.dispatchRepositoryEventMutex.Unlock()
+	fake.DispatchRepositoryEventStub = nil
+	fake.dispatchRepositoryEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DispatchRepositoryEventReturnsOnCall(i int, result1 error) {
+	fake.dispatchRepositoryEventMutex.Lock()
+	defer fake.dispatchRepositoryEventMutex.Unlock()
+	fake.DispatchRepositoryEventStub = nil
+	if fake.dispatchRepositoryEventReturnsOnCall == nil {
+		fake.dispatchRepositoryEventReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.dispatchRepositoryEventReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) DispatchWorkflow(arg1 string, arg2 string, arg3 map[string]string, arg4 string) (*models.WorkflowRun, error) {
+	fake.dispatchWorkflowMutex.Lock()
+	ret, specificReturn := fake.dispatchWorkflowReturnsOnCall[len(fake.dispatchWorkflowArgsForCall)]
+	fake.dispatchWorkflowArgsForCall = append(fake.dispatchWorkflowArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 map[string]string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("DispatchWorkflow", []interface{}{arg1, arg2, arg3, arg4})
+	fake.dispatchWorkflowMutex.Unlock()
+	if fake.DispatchWorkflowStub != nil {
+		return fake.DispatchWorkflowStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.dispatchWorkflowReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) DispatchWorkflowCallCount() int {
+	fake.dispatchWorkflowMutex.RLock()
+	defer fake.dispatchWorkflowMutex.RUnlock()
+	return len(fake.dispatchWorkflowArgsForCall)
+}
+
+func (fake *FakeGithub) DispatchWorkflowCalls(stub func(string, string, map[string]string, string) (*models.WorkflowRun, error)) {
+	fake.dispatchWorkflowMutex.Lock()
+	defer fake.dispatchWorkflowMutex.Unlock()
+	fake.DispatchWorkflowStub = stub
+}
+
+func (fake *FakeGithub) DispatchWorkflowArgsForCall(i int) (string, string, map[string]string, string) {
+	fake.dispatchWorkflowMutex.RLock()
+	defer fake.dispatchWorkflowMutex.RUnlock()
+	argsForCall := fake.dispatchWorkflowArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGithub) DispatchWorkflowReturns(result1 *models.WorkflowRun, result2 error) {
+	fake.dispatchWorkflowMutex.Lock()
+	defer fake.dispatchWorkflowMutex.Unlock()
+	fake.DispatchWorkflowStub = nil
+	fake.dispatchWorkflowReturns = struct {
+		result1 *models.WorkflowRun
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) DispatchWorkflowReturnsOnCall(i int, result1 *models.WorkflowRun, result2 error) {
+	fake.dispatchWorkflowMutex.Lock()
+	defer fake.dispatchWorkflowMutex.Unlock()
+	fake.DispatchWorkflowStub = nil
+	if fake.dispatchWorkflowReturnsOnCall == nil {
+		fake.dispatchWorkflowReturnsOnCall = make(map[int]struct {
+			result1 *models.WorkflowRun
+			result2 error
+		})
+	}
+	fake.dispatchWorkflowReturnsOnCall[i] = struct {
+		result1 *models.WorkflowRun
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) EnablePullRequestAutoMerge(arg1 string, arg2 string, arg3 string, arg4 string, arg5 string) (*models.AutoMergeRequestObject, error) {
+	fake.enablePullRequestAutoMergeMutex.Lock()
+	ret, specificReturn := fake.enablePullRequestAutoMergeReturnsOnCall[len(fake.enablePullRequestAutoMergeArgsForCall)]
+	fake.enablePullRequestAutoMergeArgsForCall = append(fake.enablePullRequestAutoMergeArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("EnablePullRequestAutoMerge", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.enablePullRequestAutoMergeMutex.Unlock()
+	if fake.EnablePullRequestAutoMergeStub != nil {
+		return fake.EnablePullRequestAutoMergeStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.enablePullRequestAutoMergeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) EnablePullRequestAutoMergeCallCount() int {
+	fake.enablePullRequestAutoMergeMutex.RLock()
+	defer fake.enablePullRequestAutoMergeMutex.RUnlock()
+	return len(fake.enablePullRequestAutoMergeArgsForCall)
+}
+
+func (fake *FakeGithub) EnablePullRequestAutoMergeCalls(stub func(string, string, string, string, string) (*models.AutoMergeRequestObject, error)) {
+	fake.enablePullRequestAutoMergeMutex.Lock()
+	defer fake.enablePullRequestAutoMergeMutex.Unlock()
+	fake.EnablePullRequestAutoMergeStub = stub
+}
+
+func (fake *FakeGithub) EnablePullRequestAutoMergeArgsForCall(i int) (string, string, string, string, string) {
+	fake.enablePullRequestAutoMergeMutex.RLock()
+	defer fake.enablePullRequestAutoMergeMutex.RUnlock()
+	argsForCall := fake.enablePullRequestAutoMergeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeGithub) EnablePullRequestAutoMergeReturns(result1 *models.AutoMergeRequestObject, result2 error) {
+	fake.enablePullRequestAutoMergeMutex.Lock()
+	defer fake.enablePullRequestAutoMergeMutex.Unlock()
+	fake.EnablePullRequestAutoMergeStub = nil
+	fake.enablePullRequestAutoMergeReturns = struct {
+		result1 *models.AutoMergeRequestObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) EnablePullRequestAutoMergeReturnsOnCall(i int, result1 *models.AutoMergeRequestObject, result2 error) {
+	fake.enablePullRequestAutoMergeMutex.Lock()
+	defer fake.enablePullRequestAutoMergeMutex.Unlock()
+	fake.EnablePullRequestAutoMergeStub = nil
+	if fake.enablePullRequestAutoMergeReturnsOnCall == nil {
+		fake.enablePullRequestAutoMergeReturnsOnCall = make(map[int]struct {
+			result1 *models.AutoMergeRequestObject
+			result2 error
+		})
+	}
+	fake.enablePullRequestAutoMergeReturnsOnCall[i] = struct {
+		result1 *models.AutoMergeRequestObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) FindPullRequest(arg1 string) (int, error) {
+	fake.findPullRequestMutex.Lock()
+	ret, specificReturn := fake.findPullRequestReturnsOnCall[len(fake.findPullRequestArgsForCall)]
+	fake.findPullRequestArgsForCall = append(fake.findPullRequestArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("FindPullRequest", []interface{}{arg1})
+	fake.findPullRequestMutex.Unlock()
+	if fake.FindPullRequestStub != nil {
+		return fake.FindPullRequestStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.findPullRequestReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) FindPullRequestCallCount() int {
+	fake.findPullRequestMutex.RLock()
+	defer fake.findPullRequestMutex.RUnlock()
+	return len(fake.findPullRequestArgsForCall)
+}
+
+func (fake *FakeGithub) FindPullRequestCalls(stub func(string) (int, error)) {
+	fake.findPullRequestMutex.Lock()
+	defer fake.findPullRequestMutex.Unlock()
+	fake.FindPullRequestStub = stub
+}
+
+func (fake *FakeGithub) FindPullRequestArgsForCall(i int) string {
+	fake.findPullRequestMutex.RLock()
+	defer fake.findPullRequestMutex.RUnlock()
+	argsForCall := fake.findPullRequestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) FindPullRequestReturns(result1 int, result2 error) {
+	fake.findPullRequestMutex.Lock()
+	defer fake.findPullRequestMutex.Unlock()
+	fake.FindPullRequestStub = nil
+	fake.findPullRequestReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) FindPullRequestReturnsOnCall(i int, result1 int, result2 error) {
+	fake.findPullRequestMutex.Lock()
+	defer fake.findPullRequestMutex.Unlock()
+	fake.FindPullRequestStub = nil
+	if fake.findPullRequestReturnsOnCall == nil {
+		fake.findPullRequestReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.findPullRequestReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetOkToTest(arg1 int, arg2 string) (*models.OkToTest, error) {
+	fake.getOkToTestMutex.Lock()
+	ret, specificReturn := fake.getOkToTestReturnsOnCall[len(fake.getOkToTestArgsForCall)]
+	fake.getOkToTestArgsForCall = append(fake.getOkToTestArgsForCall, struct {
+		arg1 int
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetOkToTest", []interface{}{arg1, arg2})
+	fake.getOkToTestMutex.Unlock()
+	if fake.GetOkToTestStub != nil {
+		return fake.GetOkToTestStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getOkToTestReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) GetOkToTestCallCount() int {
+	fake.getOkToTestMutex.RLock()
+	defer fake.getOkToTestMutex.RUnlock()
+	return len(fake.getOkToTestArgsForCall)
+}
+
+func (fake *FakeGithub) GetOkToTestCalls(stub func(int, string) (*models.OkToTest, error)) {
+	fake.getOkToTestMutex.Lock()
+	defer fake.getOkToTestMutex.Unlock()
+	fake.GetOkToTestStub = stub
+}
+
+func (fake *FakeGithub) GetOkToTestArgsForCall(i int) (int, string) {
+	fake.getOkToTestMutex.RLock()
+	defer fake.getOkToTestMutex.RUnlock()
+	argsForCall := fake.getOkToTestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) GetOkToTestReturns(result1 *models.OkToTest, result2 error) {
+	fake.getOkToTestMutex.Lock()
+	defer fake.getOkToTestMutex.Unlock()
+	fake.GetOkToTestStub = nil
+	fake.getOkToTestReturns = struct {
+		result1 *models.OkToTest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetOkToTestReturnsOnCall(i int, result1 *models.OkToTest, result2 error) {
+	fake.getOkToTestMutex.Lock()
+	defer fake.getOkToTestMutex.Unlock()
+	fake.GetOkToTestStub = nil
+	if fake.getOkToTestReturnsOnCall == nil {
+		fake.getOkToTestReturnsOnCall = make(map[int]struct {
+			result1 *models.OkToTest
+			result2 error
+		})
+	}
+	fake.getOkToTestReturnsOnCall[i] = struct {
+		result1 *models.OkToTest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetPullRequest(arg1 int, arg2 string) (*models.PullRequest, error) {
+	fake.getPullRequestMutex.Lock()
+	ret, specificReturn := fake.getPullRequestReturnsOnCall[len(fake.getPullRequestArgsForCall)]
+	fake.getPullRequestArgsForCall = append(fake.getPullRequestArgsForCall, struct {
+		arg1 int
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetPullRequest", []interface{}{arg1, arg2})
+	fake.getPullRequestMutex.Unlock()
+	if fake.GetPullRequestStub != nil {
+		return fake.GetPullRequestStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPullRequestReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) GetPullRequestCallCount() int {
+	fake.getPullRequestMutex.RLock()
+	defer fake.getPullRequestMutex.RUnlock()
+	return len(fake.getPullRequestArgsForCall)
+}
+
+func (fake *FakeGithub) GetPullRequestCalls(stub func(int, string) (*models.PullRequest, error)) {
+	fake.getPullRequestMutex.Lock()
+	defer fake.getPullRequestMutex.Unlock()
+	fake.GetPullRequestStub = stub
+}
+
+func (fake *FakeGithub) GetPullRequestArgsForCall(i int) (int, string) {
+	fake.getPullRequestMutex.RLock()
 	defer fake.getPullRequestMutex.RUnlock()
 	argsForCall := fake.getPullRequestArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2
@@ -200,18 +1520,271 @@ func (fake *FakeGithub) GetPullRequestReturns(result1 *models.PullRequest, resul
 	}{result1, result2}
 }
 
-func (fake *FakeGithub) GetPullRequestReturnsOnCall(i int, result1 *models.PullRequest, result2 error) {
-	fake.getPullRequestMutex.Lock()
-	defer fake.getPullRequestMutex.Unlock()
-	fake.GetPullRequestStub = nil
-	if fake.getPullRequestReturnsOnCall == nil {
-		fake.getPullRequestReturnsOnCall = make(map[int]struct {
-			result1 *models.PullRequest
+func (fake *FakeGithub) GetPullRequestReturnsOnCall(i int, result1 *models.PullRequest, result2 error) {
+	fake.getPullRequestMutex.Lock()
+	defer fake.getPullRequestMutex.Unlock()
+	fake.GetPullRequestStub = nil
+	if fake.getPullRequestReturnsOnCall == nil {
+		fake.getPullRequestReturnsOnCall = make(map[int]struct {
+			result1 *models.PullRequest
+			result2 error
+		})
+	}
+	fake.getPullRequestReturnsOnCall[i] = struct {
+		result1 *models.PullRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetPullRequestBody(arg1 int) (string, error) {
+	fake.getPullRequestBodyMutex.Lock()
+	ret, specificReturn := fake.getPullRequestBodyReturnsOnCall[len(fake.getPullRequestBodyArgsForCall)]
+	fake.getPullRequestBodyArgsForCall = append(fake.getPullRequestBodyArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("GetPullRequestBody", []interface{}{arg1})
+	fake.getPullRequestBodyMutex.Unlock()
+	if fake.GetPullRequestBodyStub != nil {
+		return fake.GetPullRequestBodyStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPullRequestBodyReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) GetPullRequestBodyCallCount() int {
+	fake.getPullRequestBodyMutex.RLock()
+	defer fake.getPullRequestBodyMutex.RUnlock()
+	return len(fake.getPullRequestBodyArgsForCall)
+}
+
+func (fake *FakeGithub) GetPullRequestBodyCalls(stub func(int) (string, error)) {
+	fake.getPullRequestBodyMutex.Lock()
+	defer fake.getPullRequestBodyMutex.Unlock()
+	fake.GetPullRequestBodyStub = stub
+}
+
+func (fake *FakeGithub) GetPullRequestBodyArgsForCall(i int) int {
+	fake.getPullRequestBodyMutex.RLock()
+	defer fake.getPullRequestBodyMutex.RUnlock()
+	argsForCall := fake.getPullRequestBodyArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) GetPullRequestBodyReturns(result1 string, result2 error) {
+	fake.getPullRequestBodyMutex.Lock()
+	defer fake.getPullRequestBodyMutex.Unlock()
+	fake.GetPullRequestBodyStub = nil
+	fake.getPullRequestBodyReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetPullRequestBodyReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getPullRequestBodyMutex.Lock()
+	defer fake.getPullRequestBodyMutex.Unlock()
+	fake.GetPullRequestBodyStub = nil
+	if fake.getPullRequestBodyReturnsOnCall == nil {
+		fake.getPullRequestBodyReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getPullRequestBodyReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetPullRequestHeadSHA(arg1 int) (string, error) {
+	fake.getPullRequestHeadSHAMutex.Lock()
+	ret, specificReturn := fake.getPullRequestHeadSHAReturnsOnCall[len(fake.getPullRequestHeadSHAArgsForCall)]
+	fake.getPullRequestHeadSHAArgsForCall = append(fake.getPullRequestHeadSHAArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("GetPullRequestHeadSHA", []interface{}{arg1})
+	fake.getPullRequestHeadSHAMutex.Unlock()
+	if fake.GetPullRequestHeadSHAStub != nil {
+		return fake.GetPullRequestHeadSHAStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPullRequestHeadSHAReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) GetPullRequestHeadSHACallCount() int {
+	fake.getPullRequestHeadSHAMutex.RLock()
+	defer fake.getPullRequestHeadSHAMutex.RUnlock()
+	return len(fake.getPullRequestHeadSHAArgsForCall)
+}
+
+func (fake *FakeGithub) GetPullRequestHeadSHACalls(stub func(int) (string, error)) {
+	fake.getPullRequestHeadSHAMutex.Lock()
+	defer fake.getPullRequestHeadSHAMutex.Unlock()
+	fake.GetPullRequestHeadSHAStub = stub
+}
+
+func (fake *FakeGithub) GetPullRequestHeadSHAArgsForCall(i int) int {
+	fake.getPullRequestHeadSHAMutex.RLock()
+	defer fake.getPullRequestHeadSHAMutex.RUnlock()
+	argsForCall := fake.getPullRequestHeadSHAArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) GetPullRequestHeadSHAReturns(result1 string, result2 error) {
+	fake.getPullRequestHeadSHAMutex.Lock()
+	defer fake.getPullRequestHeadSHAMutex.Unlock()
+	fake.GetPullRequestHeadSHAStub = nil
+	fake.getPullRequestHeadSHAReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) GetPullRequestHeadSHAReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getPullRequestHeadSHAMutex.Lock()
+	defer fake.getPullRequestHeadSHAMutex.Unlock()
+	fake.GetPullRequestHeadSHAStub = nil
+	if fake.getPullRequestHeadSHAReturnsOnCall == nil {
+		fake.getPullRequestHeadSHAReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getPullRequestHeadSHAReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListCommitChecks(arg1 string) ([]models.CommitCheck, error) {
+	fake.listCommitChecksMutex.Lock()
+	ret, specificReturn := fake.listCommitChecksReturnsOnCall[len(fake.listCommitChecksArgsForCall)]
+	fake.listCommitChecksArgsForCall = append(fake.listCommitChecksArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ListCommitChecks", []interface{}{arg1})
+	fake.listCommitChecksMutex.Unlock()
+	if fake.ListCommitChecksStub != nil {
+		return fake.ListCommitChecksStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listCommitChecksReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) ListCommitChecksCallCount() int {
+	fake.listCommitChecksMutex.RLock()
+	defer fake.listCommitChecksMutex.RUnlock()
+	return len(fake.listCommitChecksArgsForCall)
+}
+
+func (fake *FakeGithub) ListCommitChecksCalls(stub func(string) ([]models.CommitCheck, error)) {
+	fake.listCommitChecksMutex.Lock()
+	defer fake.listCommitChecksMutex.Unlock()
+	fake.ListCommitChecksStub = stub
+}
+
+func (fake *FakeGithub) ListCommitChecksArgsForCall(i int) string {
+	fake.listCommitChecksMutex.RLock()
+	defer fake.listCommitChecksMutex.RUnlock()
+	argsForCall := fake.listCommitChecksArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) ListCommitChecksReturns(result1 []models.CommitCheck, result2 error) {
+	fake.listCommitChecksMutex.Lock()
+	defer fake.listCommitChecksMutex.Unlock()
+	fake.ListCommitChecksStub = nil
+	fake.listCommitChecksReturns = struct {
+		result1 []models.CommitCheck
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListCommitChecksReturnsOnCall(i int, result1 []models.CommitCheck, result2 error) {
+	fake.listCommitChecksMutex.Lock()
+	defer fake.listCommitChecksMutex.Unlock()
+	fake.ListCommitChecksStub = nil
+	if fake.listCommitChecksReturnsOnCall == nil {
+		fake.listCommitChecksReturnsOnCall = make(map[int]struct {
+			result1 []models.CommitCheck
+			result2 error
+		})
+	}
+	fake.listCommitChecksReturnsOnCall[i] = struct {
+		result1 []models.CommitCheck
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListDeployments(arg1 string, arg2 string) ([]int64, error) {
+	fake.listDeploymentsMutex.Lock()
+	ret, specificReturn := fake.listDeploymentsReturnsOnCall[len(fake.listDeploymentsArgsForCall)]
+	fake.listDeploymentsArgsForCall = append(fake.listDeploymentsArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("ListDeployments", []interface{}{arg1, arg2})
+	fake.listDeploymentsMutex.Unlock()
+	if fake.ListDeploymentsStub != nil {
+		return fake.ListDeploymentsStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listDeploymentsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) ListDeploymentsCallCount() int {
+	fake.listDeploymentsMutex.RLock()
+	defer fake.listDeploymentsMutex.RUnlock()
+	return len(fake.listDeploymentsArgsForCall)
+}
+
+func (fake *FakeGithub) ListDeploymentsCalls(stub func(string, string) ([]int64, error)) {
+	fake.listDeploymentsMutex.Lock()
+	defer fake.listDeploymentsMutex.Unlock()
+	fake.ListDeploymentsStub = stub
+}
+
+func (fake *FakeGithub) ListDeploymentsArgsForCall(i int) (string, string) {
+	fake.listDeploymentsMutex.RLock()
+	defer fake.listDeploymentsMutex.RUnlock()
+	argsForCall := fake.listDeploymentsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) ListDeploymentsReturns(result1 []int64, result2 error) {
+	fake.listDeploymentsMutex.Lock()
+	defer fake.listDeploymentsMutex.Unlock()
+	fake.ListDeploymentsStub = nil
+	fake.listDeploymentsReturns = struct {
+		result1 []int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListDeploymentsReturnsOnCall(i int, result1 []int64, result2 error) {
+	fake.listDeploymentsMutex.Lock()
+	defer fake.listDeploymentsMutex.Unlock()
+	fake.ListDeploymentsStub = nil
+	if fake.listDeploymentsReturnsOnCall == nil {
+		fake.listDeploymentsReturnsOnCall = make(map[int]struct {
+			result1 []int64
 			result2 error
 		})
 	}
-	fake.getPullRequestReturnsOnCall[i] = struct {
-		result1 *models.PullRequest
+	fake.listDeploymentsReturnsOnCall[i] = struct {
+		result1 []int64
 		result2 error
 	}{result1, result2}
 }
@@ -279,6 +1852,132 @@ func (fake *FakeGithub) ListModifiedFilesReturnsOnCall(i int, result1 []string,
 	}{result1, result2}
 }
 
+func (fake *FakeGithub) ListPullRequestCommits(arg1 int) ([]models.CommitObject, error) {
+	fake.listPullRequestCommitsMutex.Lock()
+	ret, specificReturn := fake.listPullRequestCommitsReturnsOnCall[len(fake.listPullRequestCommitsArgsForCall)]
+	fake.listPullRequestCommitsArgsForCall = append(fake.listPullRequestCommitsArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("ListPullRequestCommits", []interface{}{arg1})
+	fake.listPullRequestCommitsMutex.Unlock()
+	if fake.ListPullRequestCommitsStub != nil {
+		return fake.ListPullRequestCommitsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listPullRequestCommitsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) ListPullRequestCommitsCallCount() int {
+	fake.listPullRequestCommitsMutex.RLock()
+	defer fake.listPullRequestCommitsMutex.RUnlock()
+	return len(fake.listPullRequestCommitsArgsForCall)
+}
+
+func (fake *FakeGithub) ListPullRequestCommitsCalls(stub func(int) ([]models.CommitObject, error)) {
+	fake.listPullRequestCommitsMutex.Lock()
+	defer fake.listPullRequestCommitsMutex.Unlock()
+	fake.ListPullRequestCommitsStub = stub
+}
+
+func (fake *FakeGithub) ListPullRequestCommitsArgsForCall(i int) int {
+	fake.listPullRequestCommitsMutex.RLock()
+	defer fake.listPullRequestCommitsMutex.RUnlock()
+	argsForCall := fake.listPullRequestCommitsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) ListPullRequestCommitsReturns(result1 []models.CommitObject, result2 error) {
+	fake.listPullRequestCommitsMutex.Lock()
+	defer fake.listPullRequestCommitsMutex.Unlock()
+	fake.ListPullRequestCommitsStub = nil
+	fake.listPullRequestCommitsReturns = struct {
+		result1 []models.CommitObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListPullRequestCommitsReturnsOnCall(i int, result1 []models.CommitObject, result2 error) {
+	fake.listPullRequestCommitsMutex.Lock()
+	defer fake.listPullRequestCommitsMutex.Unlock()
+	fake.ListPullRequestCommitsStub = nil
+	if fake.listPullRequestCommitsReturnsOnCall == nil {
+		fake.listPullRequestCommitsReturnsOnCall = make(map[int]struct {
+			result1 []models.CommitObject
+			result2 error
+		})
+	}
+	fake.listPullRequestCommitsReturnsOnCall[i] = struct {
+		result1 []models.CommitObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListPullRequestLabels(arg1 int) ([]models.LabelObject, error) {
+	fake.listPullRequestLabelsMutex.Lock()
+	ret, specificReturn := fake.listPullRequestLabelsReturnsOnCall[len(fake.listPullRequestLabelsArgsForCall)]
+	fake.listPullRequestLabelsArgsForCall = append(fake.listPullRequestLabelsArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("ListPullRequestLabels", []interface{}{arg1})
+	fake.listPullRequestLabelsMutex.Unlock()
+	if fake.ListPullRequestLabelsStub != nil {
+		return fake.ListPullRequestLabelsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listPullRequestLabelsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) ListPullRequestLabelsCallCount() int {
+	fake.listPullRequestLabelsMutex.RLock()
+	defer fake.listPullRequestLabelsMutex.RUnlock()
+	return len(fake.listPullRequestLabelsArgsForCall)
+}
+
+func (fake *FakeGithub) ListPullRequestLabelsCalls(stub func(int) ([]models.LabelObject, error)) {
+	fake.listPullRequestLabelsMutex.Lock()
+	defer fake.listPullRequestLabelsMutex.Unlock()
+	fake.ListPullRequestLabelsStub = stub
+}
+
+func (fake *FakeGithub) ListPullRequestLabelsArgsForCall(i int) int {
+	fake.listPullRequestLabelsMutex.RLock()
+	defer fake.listPullRequestLabelsMutex.RUnlock()
+	argsForCall := fake.listPullRequestLabelsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) ListPullRequestLabelsReturns(result1 []models.LabelObject, result2 error) {
+	fake.listPullRequestLabelsMutex.Lock()
+	defer fake.listPullRequestLabelsMutex.Unlock()
+	fake.ListPullRequestLabelsStub = nil
+	fake.listPullRequestLabelsReturns = struct {
+		result1 []models.LabelObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) ListPullRequestLabelsReturnsOnCall(i int, result1 []models.LabelObject, result2 error) {
+	fake.listPullRequestLabelsMutex.Lock()
+	defer fake.listPullRequestLabelsMutex.Unlock()
+	fake.ListPullRequestLabelsStub = nil
+	if fake.listPullRequestLabelsReturnsOnCall == nil {
+		fake.listPullRequestLabelsReturnsOnCall = make(map[int]struct {
+			result1 []models.LabelObject
+			result2 error
+		})
+	}
+	fake.listPullRequestLabelsReturnsOnCall[i] = struct {
+		result1 []models.LabelObject
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeGithub) ListPullRequests(arg1 []githubv4.PullRequestState) ([]*models.PullRequest, error) {
 	var arg1Copy []githubv4.PullRequestState
 	if arg1 != nil {
@@ -347,6 +2046,133 @@ func (fake *FakeGithub) ListPullRequestsReturnsOnCall(i int, result1 []*models.P
 	}{result1, result2}
 }
 
+func (fake *FakeGithub) MarkPullRequestReadyForReview(arg1 string) error {
+	fake.markPullRequestReadyForReviewMutex.Lock()
+	ret, specificReturn := fake.markPullRequestReadyForReviewReturnsOnCall[len(fake.markPullRequestReadyForReviewArgsForCall)]
+	fake.markPullRequestReadyForReviewArgsForCall = append(fake.markPullRequestReadyForReviewArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("MarkPullRequestReadyForReview", []interface{}{arg1})
+	fake.markPullRequestReadyForReviewMutex.Unlock()
+	if fake.MarkPullRequestReadyForReviewStub != nil {
+		return fake.MarkPullRequestReadyForReviewStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.markPullRequestReadyForReviewReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) MarkPullRequestReadyForReviewCallCount() int {
+	fake.markPullRequestReadyForReviewMutex.RLock()
+	defer fake.markPullRequestReadyForReviewMutex.RUnlock()
+	return len(fake.markPullRequestReadyForReviewArgsForCall)
+}
+
+func (fake *FakeGithub) MarkPullRequestReadyForReviewCalls(stub func(string) error) {
+	fake.markPullRequestReadyForReviewMutex.Lock()
+	defer fake.markPullRequestReadyForReviewMutex.Unlock()
+	fake.MarkPullRequestReadyForReviewStub = stub
+}
+
+func (fake *FakeGithub) MarkPullRequestReadyForReviewArgsForCall(i int) string {
+	fake.markPullRequestReadyForReviewMutex.RLock()
+	defer fake.markPullRequestReadyForReviewMutex.RUnlock()
+	argsForCall := fake.markPullRequestReadyForReviewArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) MarkPullRequestReadyForReviewReturns(result1 error) {
+	fake.markPullRequestReadyForReviewMutex.Lock()
+	defer fake.markPullRequestReadyForReviewMutex.Unlock()
+	fake.MarkPullRequestReadyForReviewStub = nil
+	fake.markPullRequestReadyForReviewReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) MarkPullRequestReadyForReviewReturnsOnCall(i int, result1 error) {
+	fake.markPullRequestReadyForReviewMutex.Lock()
+	defer fake.markPullRequestReadyForReviewMutex.Unlock()
+	fake.MarkPullRequestReadyForReviewStub = nil
+	if fake.markPullRequestReadyForReviewReturnsOnCall == nil {
+		fake.markPullRequestReadyForReviewReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.markPullRequestReadyForReviewReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) MergePullRequest(arg1 int, arg2 string, arg3 string, arg4 string, arg5 string) (string, error) {
+	fake.mergePullRequestMutex.Lock()
+	ret, specificReturn := fake.mergePullRequestReturnsOnCall[len(fake.mergePullRequestArgsForCall)]
+	fake.mergePullRequestArgsForCall = append(fake.mergePullRequestArgsForCall, struct {
+		arg1 int
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("MergePullRequest", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.mergePullRequestMutex.Unlock()
+	if fake.MergePullRequestStub != nil {
+		return fake.MergePullRequestStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.mergePullRequestReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) MergePullRequestCallCount() int {
+	fake.mergePullRequestMutex.RLock()
+	defer fake.mergePullRequestMutex.RUnlock()
+	return len(fake.mergePullRequestArgsForCall)
+}
+
+func (fake *FakeGithub) MergePullRequestCalls(stub func(int, string, string, string, string) (string, error)) {
+	fake.mergePullRequestMutex.Lock()
+	defer fake.mergePullRequestMutex.Unlock()
+	fake.MergePullRequestStub = stub
+}
+
+func (fake *FakeGithub) MergePullRequestArgsForCall(i int) (int, string, string, string, string) {
+	fake.mergePullRequestMutex.RLock()
+	defer fake.mergePullRequestMutex.RUnlock()
+	argsForCall := fake.mergePullRequestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeGithub) MergePullRequestReturns(result1 string, result2 error) {
+	fake.mergePullRequestMutex.Lock()
+	defer fake.mergePullRequestMutex.Unlock()
+	fake.MergePullRequestStub = nil
+	fake.mergePullRequestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) MergePullRequestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.mergePullRequestMutex.Lock()
+	defer fake.mergePullRequestMutex.Unlock()
+	fake.MergePullRequestStub = nil
+	if fake.mergePullRequestReturnsOnCall == nil {
+		fake.mergePullRequestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.mergePullRequestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeGithub) PostComment(arg1 int, arg2 string) error {
 	fake.postCommentMutex.Lock()
 	ret, specificReturn := fake.postCommentReturnsOnCall[len(fake.postCommentArgsForCall)]
@@ -408,6 +2234,127 @@ func (fake *FakeGithub) PostCommentReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeGithub) RemoveLabel(arg1 int, arg2 string) error {
+	fake.removeLabelMutex.Lock()
+	ret, specificReturn := fake.removeLabelReturnsOnCall[len(fake.removeLabelArgsForCall)]
+	fake.removeLabelArgsForCall = append(fake.removeLabelArgsForCall, struct {
+		arg1 int
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("RemoveLabel", []interface{}{arg1, arg2})
+	fake.removeLabelMutex.Unlock()
+	if fake.RemoveLabelStub != nil {
+		return fake.RemoveLabelStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.removeLabelReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) RemoveLabelCallCount() int {
+	fake.removeLabelMutex.RLock()
+	defer fake.removeLabelMutex.RUnlock()
+	return len(fake.removeLabelArgsForCall)
+}
+
+func (fake *FakeGithub) RemoveLabelCalls(stub func(int, string) error) {
+	fake.removeLabelMutex.Lock()
+	defer fake.removeLabelMutex.Unlock()
+	fake.RemoveLabelStub = stub
+}
+
+func (fake *FakeGithub) RemoveLabelArgsForCall(i int) (int, string) {
+	fake.removeLabelMutex.RLock()
+	defer fake.removeLabelMutex.RUnlock()
+	argsForCall := fake.removeLabelArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) RemoveLabelReturns(result1 error) {
+	fake.removeLabelMutex.Lock()
+	defer fake.removeLabelMutex.Unlock()
+	fake.RemoveLabelStub = nil
+	fake.removeLabelReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) RemoveLabelReturnsOnCall(i int, result1 error) {
+	fake.removeLabelMutex.Lock()
+	defer fake.removeLabelMutex.Unlock()
+	fake.RemoveLabelStub = nil
+	if fake.removeLabelReturnsOnCall == nil {
+		fake.removeLabelReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeLabelReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) ReopenPullRequest(arg1 string) error {
+	fake.reopenPullRequestMutex.Lock()
+	ret, specificReturn := fake.reopenPullRequestReturnsOnCall[len(fake.reopenPullRequestArgsForCall)]
+	fake.reopenPullRequestArgsForCall = append(fake.reopenPullRequestArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ReopenPullRequest", []interface{}{arg1})
+	fake.reopenPullRequestMutex.Unlock()
+	if fake.ReopenPullRequestStub != nil {
+		return fake.ReopenPullRequestStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.reopenPullRequestReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) ReopenPullRequestCallCount() int {
+	fake.reopenPullRequestMutex.RLock()
+	defer fake.reopenPullRequestMutex.RUnlock()
+	return len(fake.reopenPullRequestArgsForCall)
+}
+
+func (fake *FakeGithub) ReopenPullRequestCalls(stub func(string) error) {
+	fake.reopenPullRequestMutex.Lock()
+	defer fake.reopenPullRequestMutex.Unlock()
+	fake.ReopenPullRequestStub = stub
+}
+
+func (fake *FakeGithub) ReopenPullRequestArgsForCall(i int) string {
+	fake.reopenPullRequestMutex.RLock()
+	defer fake.reopenPullRequestMutex.RUnlock()
+	argsForCall := fake.reopenPullRequestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeGithub) ReopenPullRequestReturns(result1 error) {
+	fake.reopenPullRequestMutex.Lock()
+	defer fake.reopenPullRequestMutex.Unlock()
+	fake.ReopenPullRequestStub = nil
+	fake.reopenPullRequestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) ReopenPullRequestReturnsOnCall(i int, result1 error) {
+	fake.reopenPullRequestMutex.Lock()
+	defer fake.reopenPullRequestMutex.Unlock()
+	fake.ReopenPullRequestStub = nil
+	if fake.reopenPullRequestReturnsOnCall == nil {
+		fake.reopenPullRequestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.reopenPullRequestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeGithub) UpdateCommitStatus(arg1 string, arg2 string, arg3 string, arg4 string, arg5 string, arg6 string) error {
 	fake.updateCommitStatusMutex.Lock()
 	ret, specificReturn := fake.updateCommitStatusReturnsOnCall[len(fake.updateCommitStatusArgsForCall)]
@@ -473,21 +2420,265 @@ func (fake *FakeGithub) UpdateCommitStatusReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeGithub) UpdatePullRequestBody(arg1 int, arg2 string) error {
+	fake.updatePullRequestBodyMutex.Lock()
+	ret, specificReturn := fake.updatePullRequestBodyReturnsOnCall[len(fake.updatePullRequestBodyArgsForCall)]
+	fake.updatePullRequestBodyArgsForCall = append(fake.updatePullRequestBodyArgsForCall, struct {
+		arg1 int
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("UpdatePullRequestBody", []interface{}{arg1, arg2})
+	fake.updatePullRequestBodyMutex.Unlock()
+	if fake.UpdatePullRequestBodyStub != nil {
+		return fake.UpdatePullRequestBodyStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.updatePullRequestBodyReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) UpdatePullRequestBodyCallCount() int {
+	fake.updatePullRequestBodyMutex.RLock()
+	defer fake.updatePullRequestBodyMutex.RUnlock()
+	return len(fake.updatePullRequestBodyArgsForCall)
+}
+
+func (fake *FakeGithub) UpdatePullRequestBodyCalls(stub func(int, string) error) {
+	fake.updatePullRequestBodyMutex.Lock()
+	defer fake.updatePullRequestBodyMutex.Unlock()
+	fake.UpdatePullRequestBodyStub = stub
+}
+
+func (fake *FakeGithub) UpdatePullRequestBodyArgsForCall(i int) (int, string) {
+	fake.updatePullRequestBodyMutex.RLock()
+	defer fake.updatePullRequestBodyMutex.RUnlock()
+	argsForCall := fake.updatePullRequestBodyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGithub) UpdatePullRequestBodyReturns(result1 error) {
+	fake.updatePullRequestBodyMutex.Lock()
+	defer fake.updatePullRequestBodyMutex.Unlock()
+	fake.UpdatePullRequestBodyStub = nil
+	fake.updatePullRequestBodyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) UpdatePullRequestBodyReturnsOnCall(i int, result1 error) {
+	fake.updatePullRequestBodyMutex.Lock()
+	defer fake.updatePullRequestBodyMutex.Unlock()
+	fake.UpdatePullRequestBodyStub = nil
+	if fake.updatePullRequestBodyReturnsOnCall == nil {
+		fake.updatePullRequestBodyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updatePullRequestBodyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) UpdatePullRequestBranch(arg1 string, arg2 string, arg3 string) (string, error) {
+	fake.updatePullRequestBranchMutex.Lock()
+	ret, specificReturn := fake.updatePullRequestBranchReturnsOnCall[len(fake.updatePullRequestBranchArgsForCall)]
+	fake.updatePullRequestBranchArgsForCall = append(fake.updatePullRequestBranchArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("UpdatePullRequestBranch", []interface{}{arg1, arg2, arg3})
+	fake.updatePullRequestBranchMutex.Unlock()
+	if fake.UpdatePullRequestBranchStub != nil {
+		return fake.UpdatePullRequestBranchStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.updatePullRequestBranchReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGithub) UpdatePullRequestBranchCallCount() int {
+	fake.updatePullRequestBranchMutex.RLock()
+	defer fake.updatePullRequestBranchMutex.RUnlock()
+	return len(fake.updatePullRequestBranchArgsForCall)
+}
+
+func (fake *FakeGithub) UpdatePullRequestBranchCalls(stub func(string, string, string) (string, error)) {
+	fake.updatePullRequestBranchMutex.Lock()
+	defer fake.updatePullRequestBranchMutex.Unlock()
+	fake.UpdatePullRequestBranchStub = stub
+}
+
+func (fake *FakeGithub) UpdatePullRequestBranchArgsForCall(i int) (string, string, string) {
+	fake.updatePullRequestBranchMutex.RLock()
+	defer fake.updatePullRequestBranchMutex.RUnlock()
+	argsForCall := fake.updatePullRequestBranchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGithub) UpdatePullRequestBranchReturns(result1 string, result2 error) {
+	fake.updatePullRequestBranchMutex.Lock()
+	defer fake.updatePullRequestBranchMutex.Unlock()
+	fake.UpdatePullRequestBranchStub = nil
+	fake.updatePullRequestBranchReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) UpdatePullRequestBranchReturnsOnCall(i int, result1 string, result2 error) {
+	fake.updatePullRequestBranchMutex.Lock()
+	defer fake.updatePullRequestBranchMutex.Unlock()
+	fake.UpdatePullRequestBranchStub = nil
+	if fake.updatePullRequestBranchReturnsOnCall == nil {
+		fake.updatePullRequestBranchReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.updatePullRequestBranchReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGithub) UpsertComment(arg1 int, arg2 string, arg3 string) error {
+	fake.upsertCommentMutex.Lock()
+	ret, specificReturn := fake.upsertCommentReturnsOnCall[len(fake.upsertCommentArgsForCall)]
+	fake.upsertCommentArgsForCall = append(fake.upsertCommentArgsForCall, struct {
+		arg1 int
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("UpsertComment", []interface{}{arg1, arg2, arg3})
+	fake.upsertCommentMutex.Unlock()
+	if fake.UpsertCommentStub != nil {
+		return fake.UpsertCommentStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.upsertCommentReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeGithub) UpsertCommentCallCount() int {
+	fake.upsertCommentMutex.RLock()
+	defer fake.upsertCommentMutex.RUnlock()
+	return len(fake.upsertCommentArgsForCall)
+}
+
+func (fake *FakeGithub) UpsertCommentCalls(stub func(int, string, string) error) {
+	fake.upsertCommentMutex.Lock()
+	defer fake.upsertCommentMutex.Unlock()
+	fake.UpsertCommentStub = stub
+}
+
+func (fake *FakeGithub) UpsertCommentArgsForCall(i int) (int, string, string) {
+	fake.upsertCommentMutex.RLock()
+	defer fake.upsertCommentMutex.RUnlock()
+	argsForCall := fake.upsertCommentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGithub) UpsertCommentReturns(result1 error) {
+	fake.upsertCommentMutex.Lock()
+	defer fake.upsertCommentMutex.Unlock()
+	fake.UpsertCommentStub = nil
+	fake.upsertCommentReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGithub) UpsertCommentReturnsOnCall(i int, result1 error) {
+	fake.upsertCommentMutex.Lock()
+	defer fake.upsertCommentMutex.Unlock()
+	fake.UpsertCommentStub = nil
+	if fake.upsertCommentReturnsOnCall == nil {
+		fake.upsertCommentReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.upsertCommentReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeGithub) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.addLabelsMutex.RLock()
+	defer fake.addLabelsMutex.RUnlock()
+	fake.branchExistsMutex.RLock()
+	defer fake.branchExistsMutex.RUnlock()
+	fake.closePullRequestMutex.RLock()
+	defer fake.closePullRequestMutex.RUnlock()
+	fake.convertPullRequestToDraftMutex.RLock()
+	defer fake.convertPullRequestToDraftMutex.RUnlock()
+	fake.countCommitsBehindMutex.RLock()
+	defer fake.countCommitsBehindMutex.RUnlock()
+	fake.createDeploymentMutex.RLock()
+	defer fake.createDeploymentMutex.RUnlock()
+	fake.createDeploymentStatusMutex.RLock()
+	defer fake.createDeploymentStatusMutex.RUnlock()
+	fake.createPullRequestMutex.RLock()
+	defer fake.createPullRequestMutex.RUnlock()
+	fake.deleteBranchMutex.RLock()
+	defer fake.deleteBranchMutex.RUnlock()
 	fake.deletePreviousCommentsMutex.RLock()
 	defer fake.deletePreviousCommentsMutex.RUnlock()
+	fake.disablePullRequestAutoMergeMutex.RLock()
+	defer fake.disablePullRequestAutoMergeMutex.RUnlock()
+	fake.dispatchRepositoryEventMutex.RLock()
+	defer fake.dispatchRepositoryEventMutex.RUnlock()
+	fake.dispatchWorkflowMutex.RLock()
+	defer fake.dispatchWorkflowMutex.RUnlock()
+	fake.enablePullRequestAutoMergeMutex.RLock()
+	defer fake.enablePullRequestAutoMergeMutex.RUnlock()
+	fake.findPullRequestMutex.RLock()
+	defer fake.findPullRequestMutex.RUnlock()
+	fake.getOkToTestMutex.RLock()
+	defer fake.getOkToTestMutex.RUnlock()
 	fake.getPullRequestMutex.RLock()
 	defer fake.getPullRequestMutex.RUnlock()
+	fake.getPullRequestBodyMutex.RLock()
+	defer fake.getPullRequestBodyMutex.RUnlock()
+	fake.getPullRequestHeadSHAMutex.RLock()
+	defer fake.getPullRequestHeadSHAMutex.RUnlock()
+	fake.listCommitChecksMutex.RLock()
+	defer fake.listCommitChecksMutex.RUnlock()
+	fake.listDeploymentsMutex.RLock()
+	defer fake.listDeploymentsMutex.RUnlock()
 	fake.listModifiedFilesMutex.RLock()
 	defer fake.listModifiedFilesMutex.RUnlock()
+	fake.listPullRequestCommitsMutex.RLock()
+	defer fake.listPullRequestCommitsMutex.RUnlock()
+	fake.listPullRequestLabelsMutex.RLock()
+	defer fake.listPullRequestLabelsMutex.RUnlock()
 	fake.listPullRequestsMutex.RLock()
 	defer fake.listPullRequestsMutex.RUnlock()
+	fake.markPullRequestReadyForReviewMutex.RLock()
+	defer fake.markPullRequestReadyForReviewMutex.RUnlock()
+	fake.mergePullRequestMutex.RLock()
+	defer fake.mergePullRequestMutex.RUnlock()
 	fake.postCommentMutex.RLock()
 	defer fake.postCommentMutex.RUnlock()
+	fake.removeLabelMutex.RLock()
+	defer fake.removeLabelMutex.RUnlock()
+	fake.reopenPullRequestMutex.RLock()
+	defer fake.reopenPullRequestMutex.RUnlock()
 	fake.updateCommitStatusMutex.RLock()
 	defer fake.updateCommitStatusMutex.RUnlock()
+	fake.updatePullRequestBodyMutex.RLock()
+	defer fake.updatePullRequestBodyMutex.RUnlock()
+	fake.updatePullRequestBranchMutex.RLock()
+	defer fake.updatePullRequestBranchMutex.RUnlock()
+	fake.upsertCommentMutex.RLock()
+	defer fake.upsertCommentMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
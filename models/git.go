@@ -18,14 +18,21 @@ type Git interface {
 	Init(*string) error
 	Pull(string, string, int, bool, bool) error
 	RevParse(string) (string, error)
-	RevList(*string, []string, []string, bool) ([]string, error)
+	RevList(*string, []string, []string, bool, bool) ([]string, error)
 	Fetch(string, int, int, bool, bool) error
 	Checkout(string, string, bool) error
 	Merge(string, bool) error
 	Rebase(string, string, bool) error
 	GitCryptUnlock(string) error
+	Commit(string, string, string) (bool, error)
+	Push(string, string, string) error
+	CherryPick(string, string, string, []string) ([]string, error)
 }
 
+// PushedCommitTrailer is added to the message of commits pushed by the
+// resource, so that they can be recognized (and ignored) by check.
+const PushedCommitTrailer = "Pushed-by: concourse-ci"
+
 func NewGitClient(common CommonConfig, disableGitLFS bool, dir string, output io.Writer) (*GitClient, error) {
 	if common.SkipSSLVerification {
 		os.Setenv("GIT_SSL_NO_VERIFY", "true")
@@ -138,12 +145,22 @@ func (g *GitClient) RevParse(branch string) (string, error) {
 	return strings.TrimSpace(string(sha)), nil
 }
 
+func (g *GitClient) revListCount(args ...string) (int, error) {
+	cmd := exec.Command("git", append([]string{"rev-list", "--count"}, args...)...)
+	cmd.Dir = g.Directory
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("rev-list failed: %s: %s", err, string(out))
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
 // RevList retrieves the list of commits starting with (and including)
 // fromCommit (if it exists) in chronological order. If fromCommit is empty or
 // does not exist in the repo, only the latest commit will be returned.
 //
 // It also
-func (g *GitClient) RevList(fromCommit *string, paths []string, ignorePaths []string, disableCISkip bool) ([]string, error) {
+func (g *GitClient) RevList(fromCommit *string, paths []string, ignorePaths []string, disableCISkip bool, ignorePushedCommits bool) ([]string, error) {
 	missingFromCommit := fromCommit == nil || !g.commitExists(*fromCommit)
 
 	initCommitBytes, err := g.silentCommand("git", "rev-list", "--max-parents=0", "HEAD").Output()
@@ -163,8 +180,14 @@ func (g *GitClient) RevList(fromCommit *string, paths []string, ignorePaths []st
 		"rev-list", "--first-parent", logRange, "--reverse",
 	}
 
+	if !disableCISkip || ignorePushedCommits {
+		args = append(args, "--invert-grep")
+	}
 	if !disableCISkip {
-		args = append(args, "--invert-grep", `--grep=\[skip\sci\]`, `--grep=\[ci\sskip\]`)
+		args = append(args, `--grep=\[skip\sci\]`, `--grep=\[ci\sskip\]`)
+	}
+	if ignorePushedCommits {
+		args = append(args, "--grep=^"+PushedCommitTrailer+"$")
 	}
 
 	if missingFromCommit {
@@ -278,6 +301,114 @@ func (g *GitClient) Rebase(baseRef string, headSha string, submodules bool) erro
 	return nil
 }
 
+// Commit all changes in the working tree with the given author. Returns false
+// if there were no changes to commit.
+func (g *GitClient) Commit(message, authorName, authorEmail string) (bool, error) {
+	if err := g.command("git", "add", "--all").Run(); err != nil {
+		return false, fmt.Errorf("add failed: %s", err)
+	}
+
+	// Exits with 1 if there are staged changes.
+	if err := g.command("git", "diff", "--cached", "--quiet").Run(); err == nil {
+		return false, nil
+	}
+
+	cmd := g.command("git",
+		"-c", "user.name="+authorName,
+		"-c", "user.email="+authorEmail,
+		"commit", "--message", message,
+	)
+	if err := cmd.Run(); err != nil {
+		return false, fmt.Errorf("commit failed: %s", err)
+	}
+	return true, nil
+}
+
+// Push HEAD to a branch, provided that the branch still points at expectedSHA
+// and HEAD only adds commits of our own on top of it: no merge commits, and no
+// commits of the base branch (as after integration_tool merge or rebase). If
+// expectedSHA is empty, the branch must not exist yet.
+func (g *GitClient) Push(uri, branch, expectedSHA string) error {
+	if expectedSHA != "" {
+		if err := g.command("git", "merge-base", "--is-ancestor", expectedSHA, "HEAD").Run(); err != nil {
+			return fmt.Errorf("HEAD does not descend from %s", expectedSHA)
+		}
+		merges, err := g.revListCount("--merges", expectedSHA+"..HEAD")
+		if err != nil {
+			return err
+		}
+		if merges > 0 {
+			return fmt.Errorf("HEAD contains merge commits on top of %s", expectedSHA)
+		}
+		// The base branch is the only one fetched into a remote-tracking branch.
+		added, err := g.revListCount(expectedSHA + "..HEAD")
+		if err != nil {
+			return err
+		}
+		own, err := g.revListCount(expectedSHA+"..HEAD", "--not", "--remotes")
+		if err != nil {
+			return err
+		}
+		if own != added {
+			return fmt.Errorf("HEAD contains commits of the base branch on top of %s", expectedSHA)
+		}
+	}
+
+	endpoint, err := g.Endpoint(uri)
+	if err != nil {
+		return err
+	}
+
+	ref := "refs/heads/" + branch
+	cmd := g.command("git", "push", "--force-with-lease="+ref+":"+expectedSHA, endpoint, "HEAD:"+ref)
+
+	// Discard output to have zero chance of logging the access token.
+	cmd.Stdout = ioutil.Discard
+	cmd.Stderr = ioutil.Discard
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("push failed (the branch may have been updated since %s): %v", expectedSHA, err)
+	}
+	return nil
+}
+
+// CherryPick the given commits onto a new branch, created from the head of
+// branch in the repository at uri. If the commits do not apply cleanly, the
+// cherry-pick is aborted and the conflicting files are returned.
+func (g *GitClient) CherryPick(uri, branch, newBranch string, commits []string) ([]string, error) {
+	endpoint, err := g.Endpoint(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := g.command("git", "fetch", endpoint, branch)
+
+	// Discard output to have zero chance of logging the access token.
+	cmd.Stdout = ioutil.Discard
+	cmd.Stderr = ioutil.Discard
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("fetch failed: %v", err)
+	}
+	if err := g.command("git", "checkout", "-B", newBranch, "FETCH_HEAD").Run(); err != nil {
+		return nil, fmt.Errorf("checkout failed: %s", err)
+	}
+
+	args := append([]string{"cherry-pick", "-x"}, commits...)
+	if err := g.command("git", args...).Run(); err != nil {
+		output, diffErr := g.silentCommand("git", "diff", "--name-only", "--diff-filter=U").Output()
+		conflicts := strings.Fields(string(output))
+		if abortErr := g.command("git", "cherry-pick", "--abort").Run(); abortErr != nil {
+			return nil, fmt.Errorf("cherry-pick --abort failed: %s", abortErr)
+		}
+		if diffErr != nil || len(conflicts) == 0 {
+			return nil, fmt.Errorf("cherry-pick failed: %s", err)
+		}
+		return conflicts, nil
+	}
+	return nil, nil
+}
+
 // GitCryptUnlock unlocks the repository using git-crypt
 func (g *GitClient) GitCryptUnlock(base64key string) error {
 	return fmt.Errorf("GitCrypt Unsupported")
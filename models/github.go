@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v28/github"
 	"github.com/shurcooL/githubv4"
@@ -42,12 +43,44 @@ func (config GithubConfig) RepositoryURL() string {
 type Github interface {
 	ListPullRequests([]githubv4.PullRequestState) ([]*PullRequest, error)
 	GetPullRequest(int, string) (*PullRequest, error)
+	GetPullRequestHeadSHA(int) (string, error)
+	ListPullRequestLabels(int) ([]LabelObject, error)
+	GetOkToTest(int, string) (*OkToTest, error)
 	ListModifiedFiles(int) ([]string, error)
+	ListPullRequestCommits(int) ([]CommitObject, error)
+	CreatePullRequest(string, string, string, string) (int, error)
+	FindPullRequest(string) (int, error)
+	BranchExists(string) (bool, error)
 	PostComment(int, string) error
+	UpsertComment(int, string, string) error
+	AddLabels(int, []string) error
+	RemoveLabel(int, string) error
+	GetPullRequestBody(int) (string, error)
+	UpdatePullRequestBody(int, string) error
 	UpdateCommitStatus(string, string, string, string, string, string) error
+	ListCommitChecks(string) ([]CommitCheck, error)
 	DeletePreviousComments(int) error
+	CreateDeployment(string, string, string) (int64, error)
+	CreateDeploymentStatus(int64, string, string, string, string) error
+	ListDeployments(string, string) ([]int64, error)
+	MergePullRequest(int, string, string, string, string) (string, error)
+	DeleteBranch(string) error
+	ClosePullRequest(string) error
+	ReopenPullRequest(string) error
+	ConvertPullRequestToDraft(string) error
+	MarkPullRequestReadyForReview(string) error
+	EnablePullRequestAutoMerge(string, string, string, string, string) (*AutoMergeRequestObject, error)
+	DisablePullRequestAutoMerge(string) error
+	CountCommitsBehind(string, string) (int, error)
+	UpdatePullRequestBranch(string, string, string) (string, error)
+	DispatchWorkflow(string, string, map[string]string, string) (*WorkflowRun, error)
+	DispatchRepositoryEvent(string, map[string]string) error
 }
 
+// ErrMergeConflict is returned when the base branch can not be merged into a
+// pull request because of conflicts.
+var ErrMergeConflict = errors.New("pull request has conflicts with the base branch")
+
 // GithubClient for handling requests to the Github V3 and V4 APIs.
 type GithubClient struct {
 	HostingEndpoint string
@@ -173,7 +206,7 @@ func (m *GithubClient) ListPullRequests(prStates []githubv4.PullRequestState) ([
 			return nil, err
 		}
 		for _, p := range query.Repository.PullRequests.Edges {
-			labels := make([]LabelObject, len(p.Node.Labels.Edges))
+			labels := make([]LabelObject, 0, len(p.Node.Labels.Edges))
 			numApprovals := 0
 			for _, review := range p.Node.Reviews.Nodes {
 				if review.AuthorCanPushToRepository {
@@ -214,6 +247,13 @@ func (m *GithubClient) GetPullRequest(prNumber int, commitRef string) (*PullRequ
 						}
 					}
 				} `graphql:"commits(last:$commitsLast)"`
+				Labels struct {
+					Edges []struct {
+						Node struct {
+							LabelObject
+						}
+					}
+				} `graphql:"labels(first:$labelsFirst)"`
 			} `graphql:"pullRequest(number:$prNumber)"`
 		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
 	}
@@ -223,6 +263,7 @@ func (m *GithubClient) GetPullRequest(prNumber int, commitRef string) (*PullRequ
 		"repositoryName":  githubv4.String(m.Repository),
 		"prNumber":        githubv4.Int(prNumber),
 		"commitsLast":     githubv4.Int(100),
+		"labelsFirst":     githubv4.Int(100),
 	}
 
 	// TODO: Pagination - in case someone pushes > 100 commits before the build has time to start :p
@@ -230,12 +271,18 @@ func (m *GithubClient) GetPullRequest(prNumber int, commitRef string) (*PullRequ
 		return nil, err
 	}
 
+	var labels []LabelObject
+	for _, l := range query.Repository.PullRequest.Labels.Edges {
+		labels = append(labels, l.Node.LabelObject)
+	}
+
 	for _, c := range query.Repository.PullRequest.Commits.Edges {
 		if c.Node.Commit.OID == commitRef {
 			// Return as soon as we find the correct ref.
 			return &PullRequest{
 				PullRequestObject: query.Repository.PullRequest.PullRequestObject,
 				Tip:               c.Node.Commit,
+				Labels:            labels,
 			}, nil
 		}
 	}
@@ -244,6 +291,193 @@ func (m *GithubClient) GetPullRequest(prNumber int, commitRef string) (*PullRequ
 	return nil, fmt.Errorf("commit with ref '%s' does not exist", commitRef)
 }
 
+// GetPullRequestHeadSHA returns the SHA of the current head commit of a pull request.
+func (m *GithubClient) GetPullRequestHeadSHA(prNumber int) (string, error) {
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				HeadRefOid string
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
+		"repositoryOwner": githubv4.String(m.Owner),
+		"repositoryName":  githubv4.String(m.Repository),
+		"prNumber":        githubv4.Int(prNumber),
+	}
+
+	if err := m.V4.Query(context.TODO(), &query, vars); err != nil {
+		return "", err
+	}
+	return query.Repository.PullRequest.HeadRefOid, nil
+}
+
+// GetOkToTest returns whether the commits of a pull request need approval
+// before they are tested, and which were approved. An approval (the label, or
+// an ok-to-test comment by a maintainer) applies to the commit named in the
+// comment, or else to the head commit at the time it was given. The head is
+// the commit pushed last before the approval, according to the times at which
+// GitHub saw the pushes (force pushes, and the first check suite of each
+// commit), since the dates of the commits are chosen by their author. Removing
+// the label revokes all previous approvals.
+func (m *GithubClient) GetOkToTest(prNumber int, label string) (*OkToTest, error) {
+	type labelEvent struct {
+		CreatedAt githubv4.DateTime
+		Label     struct {
+			Name string
+		}
+	}
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				IsCrossRepository bool
+				AuthorAssociation githubv4.CommentAuthorAssociation
+				Commits           struct {
+					Nodes []struct {
+						Commit struct {
+							OID         string
+							CheckSuites struct {
+								Nodes []struct {
+									CreatedAt githubv4.DateTime
+								}
+							} `graphql:"checkSuites(first:10)"`
+						}
+					}
+				} `graphql:"commits(last:100)"`
+				TimelineItems struct {
+					Nodes []struct {
+						Typename                string `graphql:"__typename"`
+						HeadRefForcePushedEvent struct {
+							CreatedAt   githubv4.DateTime
+							AfterCommit struct {
+								OID string
+							}
+						} `graphql:"... on HeadRefForcePushedEvent"`
+						LabeledEvent   labelEvent `graphql:"... on LabeledEvent"`
+						UnlabeledEvent labelEvent `graphql:"... on UnlabeledEvent"`
+						IssueComment   struct {
+							CreatedAt         githubv4.DateTime
+							Body              string
+							AuthorAssociation githubv4.CommentAuthorAssociation
+						} `graphql:"... on IssueComment"`
+					}
+					PageInfo struct {
+						EndCursor   githubv4.String
+						HasNextPage bool
+					}
+				} `graphql:"timelineItems(first:$itemsFirst,after:$itemsCursor,itemTypes:$itemTypes)"`
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
+		"repositoryOwner": githubv4.String(m.Owner),
+		"repositoryName":  githubv4.String(m.Repository),
+		"prNumber":        githubv4.Int(prNumber),
+		"itemsFirst":      githubv4.Int(100),
+		"itemsCursor":     (*githubv4.String)(nil),
+		"itemTypes": []githubv4.PullRequestTimelineItemsItemType{
+			githubv4.PullRequestTimelineItemsItemTypeHeadRefForcePushedEvent,
+			githubv4.PullRequestTimelineItemsItemTypeLabeledEvent,
+			githubv4.PullRequestTimelineItemsItemTypeUnlabeledEvent,
+			githubv4.PullRequestTimelineItemsItemTypeIssueComment,
+		},
+	}
+
+	okToTest := &OkToTest{}
+	var pushes []CommitPush
+	var approvals []OkToTestApproval
+	for page := 0; ; page++ {
+		if err := m.V4.Query(context.TODO(), &query, vars); err != nil {
+			return nil, err
+		}
+		pr := query.Repository.PullRequest
+		okToTest.Required = RequiresOkToTest(PullRequestObject{
+			IsCrossRepository: pr.IsCrossRepository,
+			AuthorAssociation: pr.AuthorAssociation,
+		})
+
+		// The commits are the same on every page of the timeline.
+		if page == 0 {
+			for _, c := range pr.Commits.Nodes {
+				push := CommitPush{OID: c.Commit.OID}
+				for _, suite := range c.Commit.CheckSuites.Nodes {
+					if push.PushedAt.IsZero() || suite.CreatedAt.Before(push.PushedAt) {
+						push.PushedAt = suite.CreatedAt.Time
+					}
+				}
+				pushes = append(pushes, push)
+			}
+		}
+
+		for _, item := range pr.TimelineItems.Nodes {
+			switch item.Typename {
+			case "HeadRefForcePushedEvent":
+				event := item.HeadRefForcePushedEvent
+				pushes = append(pushes, CommitPush{OID: event.AfterCommit.OID, PushedAt: event.CreatedAt.Time})
+			case "LabeledEvent":
+				if item.LabeledEvent.Label.Name == label {
+					approvals = append(approvals, OkToTestApproval{At: item.LabeledEvent.CreatedAt.Time})
+				}
+			case "UnlabeledEvent":
+				if item.UnlabeledEvent.Label.Name == label {
+					approvals = append(approvals, OkToTestApproval{At: item.UnlabeledEvent.CreatedAt.Time, Revoke: true})
+				}
+			case "IssueComment":
+				comment := item.IssueComment
+				if !IsTrustedAssociation(comment.AuthorAssociation) {
+					continue
+				}
+				if approval, ok := ParseOkToTestComment(comment.Body, comment.CreatedAt.Time); ok {
+					approvals = append(approvals, approval)
+				}
+			}
+		}
+
+		if !pr.TimelineItems.PageInfo.HasNextPage {
+			break
+		}
+		vars["itemsCursor"] = pr.TimelineItems.PageInfo.EndCursor
+	}
+	okToTest.ApprovedCommits, okToTest.UnattributedApprovals = ApprovedCommits(pushes, approvals)
+	return okToTest, nil
+}
+
+// ListPullRequestLabels returns the current labels of a pull request.
+func (m *GithubClient) ListPullRequestLabels(prNumber int) ([]LabelObject, error) {
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				Labels struct {
+					Edges []struct {
+						Node struct {
+							LabelObject
+						}
+					}
+				} `graphql:"labels(first:$labelsFirst)"`
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
+		"repositoryOwner": githubv4.String(m.Owner),
+		"repositoryName":  githubv4.String(m.Repository),
+		"prNumber":        githubv4.Int(prNumber),
+		"labelsFirst":     githubv4.Int(100),
+	}
+
+	if err := m.V4.Query(context.TODO(), &query, vars); err != nil {
+		return nil, err
+	}
+
+	var labels []LabelObject
+	for _, l := range query.Repository.PullRequest.Labels.Edges {
+		labels = append(labels, l.Node.LabelObject)
+	}
+	return labels, nil
+}
+
 // ListModifiedFiles in a pull request (not supported by V4 API).
 func (m *GithubClient) ListModifiedFiles(prNumber int) ([]string, error) {
 	var files []string
@@ -273,6 +507,109 @@ func (m *GithubClient) ListModifiedFiles(prNumber int) ([]string, error) {
 	return files, nil
 }
 
+// ListPullRequestCommits returns all commits of a pull request in order,
+// excluding merge commits.
+func (m *GithubClient) ListPullRequestCommits(prNumber int) ([]CommitObject, error) {
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				Commits struct {
+					Nodes []struct {
+						Commit struct {
+							CommitObject
+							Parents struct {
+								TotalCount int
+							}
+						}
+					}
+					PageInfo struct {
+						EndCursor   githubv4.String
+						HasNextPage bool
+					}
+				} `graphql:"commits(first:$commitsFirst,after:$commitsCursor)"`
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
+		"repositoryOwner": githubv4.String(m.Owner),
+		"repositoryName":  githubv4.String(m.Repository),
+		"prNumber":        githubv4.Int(prNumber),
+		"commitsFirst":    githubv4.Int(100),
+		"commitsCursor":   (*githubv4.String)(nil),
+	}
+
+	var commits []CommitObject
+	for {
+		if err := m.V4.Query(context.TODO(), &query, vars); err != nil {
+			return nil, err
+		}
+		for _, c := range query.Repository.PullRequest.Commits.Nodes {
+			if c.Commit.Parents.TotalCount > 1 {
+				continue
+			}
+			commits = append(commits, c.Commit.CommitObject)
+		}
+		if !query.Repository.PullRequest.Commits.PageInfo.HasNextPage {
+			break
+		}
+		vars["commitsCursor"] = query.Repository.PullRequest.Commits.PageInfo.EndCursor
+	}
+	return commits, nil
+}
+
+// CreatePullRequest from head into base, and return its number.
+func (m *GithubClient) CreatePullRequest(base, head, title, body string) (int, error) {
+	pull, _, err := m.V3.PullRequests.Create(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		&github.NewPullRequest{
+			Base:  github.String(base),
+			Head:  github.String(head),
+			Title: github.String(title),
+			Body:  github.String(body),
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return pull.GetNumber(), nil
+}
+
+// FindPullRequest returns the number of a pull request (in any state) from a
+// branch of the repository, or 0 if there is none.
+func (m *GithubClient) FindPullRequest(head string) (int, error) {
+	pulls, _, err := m.V3.PullRequests.List(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		&github.PullRequestListOptions{
+			State:       "all",
+			Head:        m.Owner + ":" + head,
+			ListOptions: github.ListOptions{PerPage: 1},
+		},
+	)
+	if err != nil || len(pulls) == 0 {
+		return 0, err
+	}
+	return pulls[0].GetNumber(), nil
+}
+
+// BranchExists returns true if the repository has the branch.
+func (m *GithubClient) BranchExists(branch string) (bool, error) {
+	_, response, err := m.V3.Repositories.GetBranch(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		branch,
+	)
+	if err != nil && response != nil && response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 // PostComment to a pull request or issue.
 func (m *GithubClient) PostComment(prNumber int, comment string) error {
 	_, _, err := m.V3.Issues.CreateComment(
@@ -287,6 +624,61 @@ func (m *GithubClient) PostComment(prNumber int, comment string) error {
 	return err
 }
 
+// AddLabels to a pull request or issue.
+func (m *GithubClient) AddLabels(prNumber int, labels []string) error {
+	_, _, err := m.V3.Issues.AddLabelsToIssue(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		prNumber,
+		labels,
+	)
+	return err
+}
+
+// RemoveLabel from a pull request. Removing a label that is not set is not an error.
+func (m *GithubClient) RemoveLabel(prNumber int, label string) error {
+	response, err := m.V3.Issues.RemoveLabelForIssue(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		prNumber,
+		label,
+	)
+	if err != nil && response != nil && response.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// GetPullRequestBody returns the current description of a pull request.
+func (m *GithubClient) GetPullRequestBody(prNumber int) (string, error) {
+	pull, _, err := m.V3.PullRequests.Get(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		prNumber,
+	)
+	if err != nil {
+		return "", err
+	}
+	return pull.GetBody(), nil
+}
+
+// UpdatePullRequestBody replaces the description of a pull request.
+func (m *GithubClient) UpdatePullRequestBody(prNumber int, body string) error {
+	_, _, err := m.V3.PullRequests.Edit(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		prNumber,
+		&github.PullRequest{
+			Body: github.String(body),
+		},
+	)
+	return err
+}
+
 // UpdateCommitStatus for a given commit (not supported by V4 API).
 func (m *GithubClient) UpdateCommitStatus(commitRef, baseContext, statusContext, status, targetURL, description string) error {
 	if baseContext == "" {
@@ -320,6 +712,162 @@ func (m *GithubClient) UpdateCommitStatus(commitRef, baseContext, statusContext,
 	return err
 }
 
+// CreateDeployment of the given ref to an environment, and return its ID.
+func (m *GithubClient) CreateDeployment(ref, environment, description string) (int64, error) {
+	deployment, _, err := m.V3.Repositories.CreateDeployment(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		&github.DeploymentRequest{
+			Ref:         github.String(ref),
+			Environment: github.String(environment),
+			Description: github.String(description),
+			// The ref is deployed as is: do not merge the base branch into
+			// it, and do not require the statuses of the ref to succeed
+			// (they typically include the pending status of this build).
+			AutoMerge:             github.Bool(false),
+			RequiredContexts:      &[]string{},
+			TransientEnvironment:  github.Bool(true),
+			ProductionEnvironment: github.Bool(false),
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return deployment.GetID(), nil
+}
+
+// CreateDeploymentStatus for a given deployment.
+func (m *GithubClient) CreateDeploymentStatus(deploymentID int64, state, environmentURL, logURL, description string) error {
+	if logURL == "" {
+		logURL = strings.Join([]string{os.Getenv("ATC_EXTERNAL_URL"), "builds", os.Getenv("BUILD_ID")}, "/")
+	}
+
+	request := &github.DeploymentStatusRequest{
+		State:  github.String(strings.ToLower(state)),
+		LogURL: github.String(logURL),
+	}
+	if environmentURL != "" {
+		request.EnvironmentURL = github.String(environmentURL)
+	}
+	if description != "" {
+		request.Description = github.String(description)
+	}
+
+	_, _, err := m.V3.Repositories.CreateDeploymentStatus(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		deploymentID,
+		request,
+	)
+	return err
+}
+
+// ListDeployments returns the IDs of the deployments matching the (optional)
+// ref and environment, newest first.
+func (m *GithubClient) ListDeployments(ref, environment string) ([]int64, error) {
+	var ids []int64
+
+	opt := &github.DeploymentsListOptions{
+		Ref:         ref,
+		Environment: environment,
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		result, response, err := m.V3.Repositories.ListDeployments(
+			context.TODO(),
+			m.Owner,
+			m.Repository,
+			opt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range result {
+			ids = append(ids, d.GetID())
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		opt.Page = response.NextPage
+	}
+	return ids, nil
+}
+
+// ListCommitChecks returns both the statuses and the (latest) check runs of a commit.
+func (m *GithubClient) ListCommitChecks(commitRef string) ([]CommitCheck, error) {
+	var checks []CommitCheck
+
+	opt := &github.ListOptions{
+		PerPage: 100,
+	}
+	for {
+		result, response, err := m.V3.Repositories.GetCombinedStatus(
+			context.TODO(),
+			m.Owner,
+			m.Repository,
+			commitRef,
+			opt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range result.Statuses {
+			checks = append(checks, CommitCheck{
+				Name:        s.GetContext(),
+				State:       s.GetState(),
+				URL:         s.GetTargetURL(),
+				StartedAt:   s.GetCreatedAt(),
+				CompletedAt: s.GetUpdatedAt(),
+			})
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		opt.Page = response.NextPage
+	}
+
+	runOpt := &github.ListCheckRunsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		result, response, err := m.V3.Checks.ListCheckRunsForRef(
+			context.TODO(),
+			m.Owner,
+			m.Repository,
+			commitRef,
+			runOpt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range result.CheckRuns {
+			state := r.GetStatus()
+			if state == "completed" {
+				state = r.GetConclusion()
+			}
+			checks = append(checks, CommitCheck{
+				Name:        r.GetName(),
+				State:       state,
+				URL:         r.GetHTMLURL(),
+				StartedAt:   r.GetStartedAt().Time,
+				CompletedAt: r.GetCompletedAt().Time,
+			})
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		runOpt.Page = response.NextPage
+	}
+
+	return checks, nil
+}
+
 func (m *GithubClient) DeletePreviousComments(prNumber int) error {
 	var getComments struct {
 		Viewer struct {
@@ -365,6 +913,362 @@ func (m *GithubClient) DeletePreviousComments(prNumber int) error {
 	return nil
 }
 
+// UpsertComment edits the last comment by the authenticated user that
+// contains marker, or posts a new comment if there is none. The marker (e.g.
+// an HTML comment) is added to the top of the comment, so that it can be found
+// by later calls.
+func (m *GithubClient) UpsertComment(prNumber int, marker, comment string) error {
+	var getComments struct {
+		Viewer struct {
+			Login string
+		}
+		Repository struct {
+			PullRequest struct {
+				Comments struct {
+					Nodes []struct {
+						DatabaseId int64
+						Body       string
+						Author     struct {
+							Login string
+						}
+					}
+				} `graphql:"comments(last:$commentsLast)"`
+			} `graphql:"pullRequest(number:$prNumber)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	vars := map[string]interface{}{
+		"repositoryOwner": githubv4.String(m.Owner),
+		"repositoryName":  githubv4.String(m.Repository),
+		"prNumber":        githubv4.Int(prNumber),
+		"commentsLast":    githubv4.Int(100),
+	}
+
+	if err := m.V4.Query(context.TODO(), &getComments, vars); err != nil {
+		return err
+	}
+
+	body := &github.IssueComment{
+		Body: github.String(marker + "\n" + comment),
+	}
+	nodes := getComments.Repository.PullRequest.Comments.Nodes
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if nodes[i].Author.Login == getComments.Viewer.Login && strings.Contains(nodes[i].Body, marker) {
+			_, _, err := m.V3.Issues.EditComment(context.TODO(), m.Owner, m.Repository, nodes[i].DatabaseId, body)
+			return err
+		}
+	}
+	_, _, err := m.V3.Issues.CreateComment(context.TODO(), m.Owner, m.Repository, prNumber, body)
+	return err
+}
+
+// MergePullRequest merges a pull request, provided its head still matches
+// headSHA, and returns the SHA of the resulting commit.
+func (m *GithubClient) MergePullRequest(prNumber int, headSHA, method, commitTitle, commitMessage string) (string, error) {
+	result, _, err := m.V3.PullRequests.Merge(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		prNumber,
+		commitMessage,
+		&github.PullRequestOptions{
+			CommitTitle: commitTitle,
+			SHA:         headSHA,
+			MergeMethod: method,
+		},
+	)
+	if err != nil {
+		if e, ok := err.(*github.ErrorResponse); ok && e.Response != nil {
+			switch e.Response.StatusCode {
+			case http.StatusMethodNotAllowed:
+				// Returned when branch protection (required checks or
+				// reviews) or a merge conflict prevents the merge.
+				return "", fmt.Errorf("pull request is not mergeable: %s", e.Message)
+			case http.StatusConflict:
+				return "", fmt.Errorf("head of pull request does not match %s: %s", headSHA, e.Message)
+			}
+		}
+		return "", err
+	}
+	return result.GetSHA(), nil
+}
+
+// DeleteBranch deletes a branch from the repository. Deleting a branch that
+// does not exist (e.g. because it was already deleted) is not an error.
+func (m *GithubClient) DeleteBranch(branch string) error {
+	response, err := m.V3.Git.DeleteRef(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		"heads/"+branch,
+	)
+	if err != nil && response != nil && response.StatusCode == http.StatusUnprocessableEntity {
+		return nil
+	}
+	return err
+}
+
+// ClosePullRequest closes the pull request with the given node ID.
+func (m *GithubClient) ClosePullRequest(id string) error {
+	var mutation struct {
+		ClosePullRequest struct {
+			ClientMutationID string
+		} `graphql:"closePullRequest(input: $input)"`
+	}
+	input := githubv4.ClosePullRequestInput{PullRequestID: id}
+	return m.V4.Mutate(context.TODO(), &mutation, input, nil)
+}
+
+// ReopenPullRequest reopens the (closed) pull request with the given node ID.
+func (m *GithubClient) ReopenPullRequest(id string) error {
+	var mutation struct {
+		ReopenPullRequest struct {
+			ClientMutationID string
+		} `graphql:"reopenPullRequest(input: $input)"`
+	}
+	input := githubv4.ReopenPullRequestInput{PullRequestID: id}
+	return m.V4.Mutate(context.TODO(), &mutation, input, nil)
+}
+
+// ConvertPullRequestToDraft converts the pull request with the given node ID to a draft.
+func (m *GithubClient) ConvertPullRequestToDraft(id string) error {
+	var mutation struct {
+		ConvertPullRequestToDraft struct {
+			ClientMutationID string
+		} `graphql:"convertPullRequestToDraft(input: $input)"`
+	}
+	input := githubv4.ConvertPullRequestToDraftInput{PullRequestID: id}
+	return m.V4.Mutate(context.TODO(), &mutation, input, nil)
+}
+
+// MarkPullRequestReadyForReview marks the (draft) pull request with the given node ID as ready for review.
+func (m *GithubClient) MarkPullRequestReadyForReview(id string) error {
+	var mutation struct {
+		MarkPullRequestReadyForReview struct {
+			ClientMutationID string
+		} `graphql:"markPullRequestReadyForReview(input: $input)"`
+	}
+	input := githubv4.MarkPullRequestReadyForReviewInput{PullRequestID: id}
+	return m.V4.Mutate(context.TODO(), &mutation, input, nil)
+}
+
+// EnablePullRequestAutoMerge enables auto-merge on the pull request with the
+// given node ID, provided its head still matches headSHA.
+func (m *GithubClient) EnablePullRequestAutoMerge(id, headSHA, method, commitHeadline, commitBody string) (*AutoMergeRequestObject, error) {
+	var mutation struct {
+		EnablePullRequestAutoMerge struct {
+			PullRequest struct {
+				AutoMergeRequest AutoMergeRequestObject
+			}
+		} `graphql:"enablePullRequestAutoMerge(input: $input)"`
+	}
+
+	input := githubv4.EnablePullRequestAutoMergeInput{PullRequestID: id}
+	if headSHA != "" {
+		input.ExpectedHeadOid = githubv4.NewGitObjectID(githubv4.GitObjectID(headSHA))
+	}
+	if method != "" {
+		mergeMethod := githubv4.PullRequestMergeMethod(strings.ToUpper(method))
+		input.MergeMethod = &mergeMethod
+	}
+	if commitHeadline != "" {
+		input.CommitHeadline = githubv4.NewString(githubv4.String(commitHeadline))
+	}
+	if commitBody != "" {
+		input.CommitBody = githubv4.NewString(githubv4.String(commitBody))
+	}
+
+	if err := m.V4.Mutate(context.TODO(), &mutation, input, nil); err != nil {
+		// The mutation error is not very descriptive when auto-merge has not
+		// been allowed in the repository settings, so check for it explicitly.
+		var query struct {
+			Repository struct {
+				AutoMergeAllowed bool
+			} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+		}
+		vars := map[string]interface{}{
+			"repositoryOwner": githubv4.String(m.Owner),
+			"repositoryName":  githubv4.String(m.Repository),
+		}
+		if qerr := m.V4.Query(context.TODO(), &query, vars); qerr == nil && !query.Repository.AutoMergeAllowed {
+			return nil, fmt.Errorf("auto-merge is not allowed in repository %s/%s", m.Owner, m.Repository)
+		}
+		return nil, err
+	}
+	return &mutation.EnablePullRequestAutoMerge.PullRequest.AutoMergeRequest, nil
+}
+
+// DisablePullRequestAutoMerge disables auto-merge on the pull request with the given node ID.
+func (m *GithubClient) DisablePullRequestAutoMerge(id string) error {
+	var mutation struct {
+		DisablePullRequestAutoMerge struct {
+			ClientMutationID string
+		} `graphql:"disablePullRequestAutoMerge(input: $input)"`
+	}
+	input := githubv4.DisablePullRequestAutoMergeInput{PullRequestID: id}
+	return m.V4.Mutate(context.TODO(), &mutation, input, nil)
+}
+
+// CountCommitsBehind returns the number of commits in base that are not in head.
+func (m *GithubClient) CountCommitsBehind(base, head string) (int, error) {
+	comparison, _, err := m.V3.Repositories.CompareCommits(
+		context.TODO(),
+		m.Owner,
+		m.Repository,
+		base,
+		head,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return comparison.GetBehindBy(), nil
+}
+
+// UpdatePullRequestBranchInput is githubv4.UpdatePullRequestBranchInput with
+// the updateMethod field, which is missing from the version of githubv4 in use.
+type UpdatePullRequestBranchInput struct {
+	PullRequestID   githubv4.ID           `json:"pullRequestId"`
+	ExpectedHeadOid *githubv4.GitObjectID `json:"expectedHeadOid,omitempty"`
+	UpdateMethod    string                `json:"updateMethod,omitempty"`
+}
+
+// UpdatePullRequestBranch updates the pull request with the given node ID with
+// the latest changes of its base branch, and returns its new head SHA.
+func (m *GithubClient) UpdatePullRequestBranch(id, headSHA, method string) (string, error) {
+	var mutation struct {
+		UpdatePullRequestBranch struct {
+			PullRequest struct {
+				HeadRefOid string
+			}
+		} `graphql:"updatePullRequestBranch(input: $input)"`
+	}
+
+	input := UpdatePullRequestBranchInput{PullRequestID: id, UpdateMethod: strings.ToUpper(method)}
+	if headSHA != "" {
+		input.ExpectedHeadOid = githubv4.NewGitObjectID(githubv4.GitObjectID(headSHA))
+	}
+
+	if err := m.V4.Mutate(context.TODO(), &mutation, input, nil); err != nil {
+		// Check whether the update failed because of conflicts, so that it
+		// can be told apart from other errors.
+		var query struct {
+			Node struct {
+				PullRequest struct {
+					Mergeable githubv4.MergeableState
+				} `graphql:"... on PullRequest"`
+			} `graphql:"node(id:$id)"`
+		}
+		vars := map[string]interface{}{
+			"id": githubv4.ID(id),
+		}
+		if qerr := m.V4.Query(context.TODO(), &query, vars); qerr == nil && query.Node.PullRequest.Mergeable == githubv4.MergeableStateConflicting {
+			return "", ErrMergeConflict
+		}
+		return "", err
+	}
+	return mutation.UpdatePullRequestBranch.PullRequest.HeadRefOid, nil
+}
+
+// DispatchWorkflow triggers a workflow_dispatch event for a GitHub Actions
+// workflow (ID or file name) on the given ref. The API does not return the
+// run that was triggered, so it is looked up afterwards by dispatchID, which
+// the caller passes in an input that the workflow shows in the name of its
+// runs. The returned run is nil if dispatchID is empty or no run has it.
+func (m *GithubClient) DispatchWorkflow(workflow, ref string, inputs map[string]string, dispatchID string) (*WorkflowRun, error) {
+	u := fmt.Sprintf("repos/%s/%s/actions/workflows/%s/dispatches", m.Owner, m.Repository, url.PathEscape(workflow))
+	body := struct {
+		Ref    string            `json:"ref"`
+		Inputs map[string]string `json:"inputs,omitempty"`
+	}{
+		Ref:    ref,
+		Inputs: inputs,
+	}
+
+	// Allow for some clock skew when looking for runs created after the dispatch.
+	dispatched := time.Now().Add(-time.Minute)
+	req, err := m.V3.NewRequest(http.MethodPost, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.V3.Do(context.TODO(), req, nil); err != nil {
+		return nil, err
+	}
+	if dispatchID == "" {
+		return nil, nil
+	}
+
+	// Runs take a moment to show up after the dispatch.
+	for attempt := 0; attempt < 5; attempt++ {
+		time.Sleep(2 * time.Second)
+
+		runs, err := m.listWorkflowDispatchRuns(workflow, ref, dispatched)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range runs {
+			if strings.Contains(r.title, dispatchID) {
+				return &r.WorkflowRun, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// workflowDispatchRun is a run, along with its name and display title.
+type workflowDispatchRun struct {
+	WorkflowRun
+	title string
+}
+
+func (m *GithubClient) listWorkflowDispatchRuns(workflow, ref string, since time.Time) ([]workflowDispatchRun, error) {
+	u := fmt.Sprintf("repos/%s/%s/actions/workflows/%s/runs?event=workflow_dispatch&branch=%s&created=%s",
+		m.Owner, m.Repository, url.PathEscape(workflow), url.QueryEscape(ref), url.QueryEscape(">="+since.UTC().Format(time.RFC3339)))
+	req, err := m.V3.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		WorkflowRuns []struct {
+			ID           int64  `json:"id"`
+			HTMLURL      string `json:"html_url"`
+			Name         string `json:"name"`
+			DisplayTitle string `json:"display_title"`
+		} `json:"workflow_runs"`
+	}
+	if _, err := m.V3.Do(context.TODO(), req, &result); err != nil {
+		return nil, err
+	}
+
+	runs := make([]workflowDispatchRun, len(result.WorkflowRuns))
+	for i, r := range result.WorkflowRuns {
+		runs[i] = workflowDispatchRun{
+			WorkflowRun: WorkflowRun{ID: r.ID, URL: r.HTMLURL},
+			title:       r.Name + "\n" + r.DisplayTitle,
+		}
+	}
+	return runs, nil
+}
+
+// DispatchRepositoryEvent triggers a repository_dispatch event.
+func (m *GithubClient) DispatchRepositoryEvent(eventType string, clientPayload map[string]string) error {
+	u := fmt.Sprintf("repos/%s/%s/dispatches", m.Owner, m.Repository)
+	body := struct {
+		EventType     string            `json:"event_type"`
+		ClientPayload map[string]string `json:"client_payload,omitempty"`
+	}{
+		EventType:     eventType,
+		ClientPayload: clientPayload,
+	}
+
+	req, err := m.V3.NewRequest(http.MethodPost, u, body)
+	if err != nil {
+		return err
+	}
+	_, err = m.V3.Do(context.TODO(), req, nil)
+	return err
+}
+
 func parseRepository(s string) (string, string, error) {
 	parts := strings.Split(s, "/")
 	if len(parts) != 2 {
@@ -1,6 +1,12 @@
 package models
 
-import "github.com/shurcooL/githubv4"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
 
 // Metadata output from get/put steps.
 type Metadata []*MetadataField
@@ -10,6 +16,16 @@ func (m *Metadata) Add(name, value string) {
 	*m = append(*m, &MetadataField{Name: name, Value: value})
 }
 
+// Get the value of the MetadataField with the given name.
+func (m Metadata) Get(name string) (string, bool) {
+	for _, f := range m {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
 // MetadataField ...
 type MetadataField struct {
 	Name  string `json:"name"`
@@ -36,11 +52,34 @@ type PullRequestObject struct {
 	Repository  struct {
 		URL string
 	}
-	IsCrossRepository bool
-	IsDraft           bool
-	State             githubv4.PullRequestState
-	ClosedAt          githubv4.DateTime
-	MergedAt          githubv4.DateTime
+	HeadRepository struct {
+		URL   string
+		Owner struct {
+			Login string
+		}
+	}
+	Author struct {
+		Login    string
+		Typename string `graphql:"__typename"`
+	}
+	AuthorAssociation   githubv4.CommentAuthorAssociation
+	IsCrossRepository   bool
+	MaintainerCanModify bool
+	Additions           int
+	Deletions           int
+	IsDraft             bool
+	State               githubv4.PullRequestState
+	ClosedAt            githubv4.DateTime
+	MergedAt            githubv4.DateTime
+}
+
+// AuthorLogin returns the login of the author of a PR, with the [bot] suffix
+// that GitHub shows for apps (and that the V4 API omits).
+func (p *PullRequestObject) AuthorLogin() string {
+	if p.Author.Typename == "Bot" {
+		return p.Author.Login + "[bot]"
+	}
+	return p.Author.Login
 }
 
 // UpdatedDate returns the last time a PR was updated, either by commit
@@ -82,3 +121,174 @@ type ChangedFileObject struct {
 type LabelObject struct {
 	Name string
 }
+
+// AutoMergeRequestObject represents the GraphQL auto merge request node.
+// https://docs.github.com/en/graphql/reference/objects#automergerequest
+type AutoMergeRequestObject struct {
+	EnabledAt   githubv4.DateTime
+	MergeMethod githubv4.PullRequestMergeMethod
+}
+
+// CommitCheck represents either a commit status or a check run on a commit.
+type CommitCheck struct {
+	// Name is the context of a status, or the name of a check run.
+	Name string
+	// State is the state of a status, the status of a check run that has not
+	// yet completed, or the conclusion of a completed check run.
+	State       string
+	URL         string
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+// OkToTestCommand is the comment with which a maintainer approves the head
+// commit of a pull request for testing, optionally followed by the SHA of the
+// approved commit.
+const OkToTestCommand = "/ok-to-test"
+
+// DefaultOkToTestLabel is the label with which a maintainer approves the head
+// commit of a pull request for testing, unless configured otherwise.
+const DefaultOkToTestLabel = "ok-to-test"
+
+// OkToTest describes whether the commits of a pull request may be tested.
+type OkToTest struct {
+	// Required if the pull request is from a fork, by an untrusted author.
+	Required bool
+	// Head commits that a maintainer approved with a label or a comment.
+	ApprovedCommits []string
+	// Approvals without a SHA that could not be applied to a commit, because
+	// GitHub has no push times for the commits (e.g. there are no check suites).
+	UnattributedApprovals int
+}
+
+// Warning explains why approvals were not applied, if any were not.
+func (o *OkToTest) Warning(prNumber int) string {
+	if !o.Required || o.UnattributedApprovals == 0 {
+		return ""
+	}
+	return fmt.Sprintf("ok-to-test: %d approval(s) of #%d could not be applied to a commit, because GitHub has no push "+
+		"times for its commits (they come from check suites): approve a commit explicitly by commenting `%s <sha>`",
+		o.UnattributedApprovals, prNumber, OkToTestCommand)
+}
+
+// Allows returns true if the commit may be tested.
+func (o *OkToTest) Allows(sha string) bool {
+	if !o.Required {
+		return true
+	}
+	for _, c := range o.ApprovedCommits {
+		if c == sha {
+			return true
+		}
+	}
+	return false
+}
+
+// CommitPush is a push of a commit to the head branch of a pull request, at a
+// time recorded by GitHub (zero if unknown). Unlike the dates of the commit
+// itself, this time can not be chosen by its author.
+type CommitPush struct {
+	OID      string
+	PushedAt time.Time
+}
+
+// OkToTestApproval is an approval by a maintainer (or its revocation), at a
+// time recorded by GitHub.
+type OkToTestApproval struct {
+	At time.Time
+	// SHA (or a prefix of it) of the approved commit, if given.
+	SHA string
+	// Revoke all approvals given before, i.e. the label was removed.
+	Revoke bool
+}
+
+// ParseOkToTestComment returns the approval given by a comment, if any.
+func ParseOkToTestComment(body string, at time.Time) (OkToTestApproval, bool) {
+	fields := strings.Fields(body)
+	if len(fields) == 0 || len(fields) > 2 || fields[0] != OkToTestCommand {
+		return OkToTestApproval{}, false
+	}
+	approval := OkToTestApproval{At: at}
+	if len(fields) == 2 {
+		if !isCommitSHAPrefix(fields[1]) {
+			return OkToTestApproval{}, false
+		}
+		approval.SHA = strings.ToLower(fields[1])
+	}
+	return approval, true
+}
+
+func isCommitSHAPrefix(s string) bool {
+	if len(s) < 7 || len(s) > 40 {
+		return false
+	}
+	for _, c := range strings.ToLower(s) {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// ApprovedCommits returns the commits approved by a list of approvals, in the
+// order they were given. An approval with a SHA approves that commit. Otherwise
+// it approves the head at the time, which is the commit pushed last before it.
+// Approvals without a SHA approve nothing if no push time is known, and are
+// counted as unattributed.
+func ApprovedCommits(pushes []CommitPush, approvals []OkToTestApproval) (approved []string, unattributed int) {
+	for _, a := range approvals {
+		switch {
+		case a.Revoke:
+			approved, unattributed = nil, 0
+		case a.SHA != "":
+			for _, p := range pushes {
+				if strings.HasPrefix(p.OID, a.SHA) && !containsString(approved, p.OID) {
+					approved = append(approved, p.OID)
+				}
+			}
+		default:
+			var head CommitPush
+			for _, p := range pushes {
+				if !p.PushedAt.IsZero() && !p.PushedAt.After(a.At) && p.PushedAt.After(head.PushedAt) {
+					head = p
+				}
+			}
+			if head.OID != "" {
+				approved = append(approved, head.OID)
+			} else {
+				unattributed++
+			}
+		}
+	}
+	return approved, unattributed
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresOkToTest returns true if a pull request is from a fork, and its
+// author does not have write access to the repository.
+func RequiresOkToTest(p PullRequestObject) bool {
+	return p.IsCrossRepository && !IsTrustedAssociation(p.AuthorAssociation)
+}
+
+// IsTrustedAssociation returns true for authors with write access to the repository.
+func IsTrustedAssociation(association githubv4.CommentAuthorAssociation) bool {
+	switch association {
+	case githubv4.CommentAuthorAssociationOwner, githubv4.CommentAuthorAssociationMember, githubv4.CommentAuthorAssociationCollaborator:
+		return true
+	}
+	return false
+}
+
+// WorkflowRun represents a run of a GitHub Actions workflow.
+type WorkflowRun struct {
+	ID  int64
+	URL string
+}
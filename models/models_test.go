@@ -0,0 +1,108 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovedCommits(t *testing.T) {
+	at := func(minute int) time.Time {
+		return time.Date(2021, 6, 1, 12, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		description  string
+		pushes       []models.CommitPush
+		approvals    []models.OkToTestApproval
+		expected     []string
+		unattributed int
+	}{
+		{
+			description: "approval applies to the head at the time",
+			pushes:      []models.CommitPush{{OID: "aaa", PushedAt: at(1)}, {OID: "bbb", PushedAt: at(2)}},
+			approvals:   []models.OkToTestApproval{{At: at(3)}},
+			expected:    []string{"bbb"},
+		},
+		{
+			// The commit is listed first because of its (backdated) commit
+			// date, but was pushed after the approval.
+			description: "approval does not apply to a backdated commit pushed after it",
+			pushes:      []models.CommitPush{{OID: "evil", PushedAt: at(5)}, {OID: "aaa", PushedAt: at(1)}},
+			approvals:   []models.OkToTestApproval{{At: at(3)}},
+			expected:    []string{"aaa"},
+		},
+		{
+			description: "approval applies to the commit force pushed last",
+			pushes: []models.CommitPush{
+				{OID: "aaa", PushedAt: at(1)},
+				{OID: "bbb", PushedAt: at(2)},
+				{OID: "aaa", PushedAt: at(3)},
+			},
+			approvals: []models.OkToTestApproval{{At: at(4)}},
+			expected:  []string{"aaa"},
+		},
+		{
+			description:  "approval applies to nothing without push times",
+			pushes:       []models.CommitPush{{OID: "aaa"}},
+			approvals:    []models.OkToTestApproval{{At: at(3)}},
+			expected:     nil,
+			unattributed: 1,
+		},
+		{
+			description: "approval with a SHA applies to that commit",
+			pushes:      []models.CommitPush{{OID: "abcdef1234"}, {OID: "bbb", PushedAt: at(2)}},
+			approvals:   []models.OkToTestApproval{{At: at(3), SHA: "abcdef1"}},
+			expected:    []string{"abcdef1234"},
+		},
+		{
+			description: "removing the label revokes previous approvals",
+			pushes:      []models.CommitPush{{OID: "aaa", PushedAt: at(1)}, {OID: "bbb", PushedAt: at(4)}},
+			approvals:   []models.OkToTestApproval{{At: at(2)}, {At: at(3), Revoke: true}, {At: at(5)}},
+			expected:    []string{"bbb"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			approved, unattributed := models.ApprovedCommits(tc.pushes, tc.approvals)
+			assert.Equal(t, tc.expected, approved)
+			assert.Equal(t, tc.unattributed, unattributed)
+		})
+	}
+}
+
+func TestParseOkToTestComment(t *testing.T) {
+	tests := []struct {
+		body     string
+		ok       bool
+		expected string
+	}{
+		{body: "/ok-to-test", ok: true},
+		{body: "  /ok-to-test\n", ok: true},
+		{body: "/ok-to-test ABCDEF1", ok: true, expected: "abcdef1"},
+		{body: "/ok-to-test abc", ok: false},
+		{body: "/ok-to-test please", ok: false},
+		{body: "/ok-to-test abcdef1 now", ok: false},
+		{body: "looks /ok-to-test", ok: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.body, func(t *testing.T) {
+			approval, ok := models.ParseOkToTestComment(tc.body, time.Time{})
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, approval.SHA)
+		})
+	}
+}
+
+func TestOkToTestWarning(t *testing.T) {
+	okToTest := &models.OkToTest{Required: true, UnattributedApprovals: 1}
+	assert.Contains(t, okToTest.Warning(7), "1 approval(s) of #7 could not be applied")
+	assert.Contains(t, okToTest.Warning(7), "`/ok-to-test <sha>`")
+
+	okToTest.Required = false
+	assert.Equal(t, "", okToTest.Warning(7))
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"os"
+	"strings"
+)
+
+// IsValidStatus returns true if status is a commit status state accepted by Github.
+func IsValidStatus(status string) bool {
+	allowed := []string{"success", "pending", "failure", "error"}
+	for _, a := range allowed {
+		if strings.ToLower(status) == a {
+			return true
+		}
+	}
+	return false
+}
+
+// SafeExpandEnv expands the Concourse build metadata environment variables in
+// s, and leaves any other variables untouched.
+func SafeExpandEnv(s string) string {
+	return os.Expand(s, expandBuildVar)
+}
+
+// SafeExpandMetadata behaves like SafeExpandEnv, but also expands the fields of
+// the metadata written by get (e.g. $title or $head_sha).
+func SafeExpandMetadata(s string, metadata Metadata) string {
+	return os.Expand(s, func(v string) string {
+		if value, ok := metadata.Get(v); ok {
+			return value
+		}
+		return expandBuildVar(v)
+	})
+}
+
+func expandBuildVar(v string) string {
+	switch v {
+	case "BUILD_ID", "BUILD_NAME", "BUILD_JOB_NAME", "BUILD_PIPELINE_NAME", "BUILD_TEAM_NAME", "ATC_EXTERNAL_URL":
+		return os.Getenv(v)
+	}
+	return "$" + v
+}
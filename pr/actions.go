@@ -0,0 +1,58 @@
+package pr
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+)
+
+// actionRunner runs the actions of a put, and records their outcome in the
+// metadata as action_<name>.
+type actionRunner struct {
+	metadata *models.Metadata
+	// Record failed actions and carry on with the next one, instead of
+	// failing the put.
+	continueOnError bool
+	// Changes planned by the actions with dry_run, if enabled.
+	plan      *dryRunPlan
+	completed []string
+}
+
+// run an action. The returned error lists the actions that already completed,
+// since the put will not output any metadata when it fails.
+func (r *actionRunner) run(name string, action func() error) error {
+	var planned int
+	if r.plan != nil {
+		planned = len(r.plan.planned)
+	}
+	if err := action(); err != nil {
+		r.metadata.Add("action_"+name, fmt.Sprintf("failed: %s", err))
+		if r.continueOnError {
+			return nil
+		}
+		if len(r.completed) > 0 {
+			return fmt.Errorf("%v (completed actions: %s)", err, strings.Join(r.completed, ", "))
+		}
+		return err
+	}
+	switch {
+	case r.plan == nil:
+		r.metadata.Add("action_"+name, "success")
+	case len(r.plan.planned) == planned:
+		r.metadata.Add("action_"+name, "no changes")
+	default:
+		r.metadata.Add("action_"+name, "planned: "+strings.Join(r.plan.planned[planned:], "; "))
+	}
+	r.completed = append(r.completed, name)
+	return nil
+}
+
+// commentMarker identifies a comment posted for a commit by a build, so that a
+// retry of the same put does not post it again.
+func commentMarker(commitRef, comment string) string {
+	hash := sha256.Sum256([]byte(os.Getenv("BUILD_ID") + "\n" + commitRef + "\n" + comment))
+	return fmt.Sprintf("<!-- concourse-ci:comment:%x -->", hash[:8])
+}
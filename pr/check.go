@@ -1,8 +1,29 @@
 package pr
 
-import "github.com/cloudfoundry-community/github-pr-instances-resource/models"
+import (
+	"fmt"
+	"os"
+
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+	"github.com/cloudfoundry-community/github-pr-instances-resource/prlist"
+)
+
+func Check(request CheckRequest, github models.Github, git models.Git) (CheckResponse, error) {
+	// Hold back new commits while the pull request has an ignored label.
+	if len(request.Source.IgnoreLabels) > 0 {
+		labels, err := github.ListPullRequestLabels(request.Source.Number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list labels: %s", err)
+		}
+		ignored, err := prlist.HasMatchingLabel(labels, request.Source.IgnoreLabels)
+		if err != nil {
+			return nil, fmt.Errorf("ignore label match failed: %s", err)
+		}
+		if ignored {
+			return CheckResponse{}, nil
+		}
+	}
 
-func Check(request CheckRequest, git models.Git) (CheckResponse, error) {
 	if err := git.Init(nil); err != nil {
 		return CheckResponse{}, err
 	}
@@ -16,14 +37,29 @@ func Check(request CheckRequest, git models.Git) (CheckResponse, error) {
 	if request.Version != nil {
 		fromCommit = &request.Version.Ref
 	}
-	commits, err := git.RevList(fromCommit, request.Source.Paths, request.Source.IgnorePaths, request.Source.DisableCISkip)
+	commits, err := git.RevList(fromCommit, request.Source.Paths, request.Source.IgnorePaths, request.Source.DisableCISkip, request.Source.IgnorePushedCommits)
 	if err != nil {
 		return nil, err
 	}
 
-	response := make(CheckResponse, len(commits))
-	for i, commit := range commits {
-		response[i] = Version{Ref: commit}
+	// Never emit commits of untrusted forks that were not approved for testing.
+	okToTest := &models.OkToTest{}
+	if request.Source.RequireOkToTest && len(commits) > 0 {
+		okToTest, err = github.GetOkToTest(request.Source.Number, request.Source.okToTestLabel())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ok-to-test approvals: %s", err)
+		}
+		// Shown in the check logs, since the approval seemingly has no effect.
+		if warning := okToTest.Warning(request.Source.Number); warning != "" {
+			fmt.Fprintln(os.Stderr, warning)
+		}
+	}
+
+	response := CheckResponse{}
+	for _, commit := range commits {
+		if okToTest.Allows(commit) {
+			response = append(response, Version{Ref: commit})
+		}
 	}
 
 	return response, nil
@@ -0,0 +1,79 @@
+package pr_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models/fakes"
+	"github.com/cloudfoundry-community/github-pr-instances-resource/pr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		description string
+		source      pr.Source
+		commits     []string
+		labels      []models.LabelObject
+		okToTest    *models.OkToTest
+		expected    pr.CheckResponse
+	}{
+		{
+			description: "check returns new commits",
+			source:      pr.Source{Number: 1},
+			commits:     []string{"sha1", "sha2"},
+			expected:    pr.CheckResponse{{Ref: "sha1"}, {Ref: "sha2"}},
+		},
+		{
+			description: "check returns nothing while the PR has an ignored label",
+			source:      pr.Source{Number: 1, IgnoreLabels: []string{"on-*"}},
+			commits:     []string{"sha1", "sha2"},
+			labels:      []models.LabelObject{{Name: "on-hold"}},
+			expected:    pr.CheckResponse{},
+		},
+		{
+			description: "check returns new commits when the PR has no ignored label",
+			source:      pr.Source{Number: 1, IgnoreLabels: []string{"on-*"}},
+			commits:     []string{"sha1", "sha2"},
+			labels:      []models.LabelObject{{Name: "bug"}},
+			expected:    pr.CheckResponse{{Ref: "sha1"}, {Ref: "sha2"}},
+		},
+		{
+			description: "check only returns approved commits of untrusted PRs",
+			source:      pr.Source{Number: 1, RequireOkToTest: true},
+			commits:     []string{"sha1", "sha2", "sha3"},
+			okToTest:    &models.OkToTest{Required: true, ApprovedCommits: []string{"sha2"}},
+			expected:    pr.CheckResponse{{Ref: "sha2"}},
+		},
+		{
+			description: "check returns all commits of trusted PRs",
+			source:      pr.Source{Number: 1, RequireOkToTest: true},
+			commits:     []string{"sha1", "sha2"},
+			okToTest:    &models.OkToTest{Required: false},
+			expected:    pr.CheckResponse{{Ref: "sha1"}, {Ref: "sha2"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.ListPullRequestLabelsReturns(tc.labels, nil)
+			github.GetOkToTestReturns(tc.okToTest, nil)
+
+			git := new(fakes.FakeGit)
+			git.RevListReturns(tc.commits, nil)
+
+			output, err := pr.Check(pr.CheckRequest{Source: tc.source}, github, git)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, output)
+			}
+			if tc.source.RequireOkToTest {
+				if assert.Equal(t, 1, github.GetOkToTestCallCount()) {
+					number, label := github.GetOkToTestArgsForCall(0)
+					assert.Equal(t, 1, number)
+					assert.Equal(t, "ok-to-test", label)
+				}
+			}
+		})
+	}
+}
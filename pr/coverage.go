@@ -0,0 +1,270 @@
+package pr
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// coverageCommentMarker identifies the coverage comment, which is updated by
+// later puts instead of posting a new one.
+const coverageCommentMarker = "<!-- concourse-ci:coverage -->"
+
+// fileCoverage is the number of covered and coverable lines (or statements
+// for Go cover profiles) of a file.
+type fileCoverage struct {
+	Covered int
+	Total   int
+}
+
+// coverageReport maps file paths, as written in the coverage file, to their coverage.
+type coverageReport map[string]*fileCoverage
+
+func (r coverageReport) add(file string, covered, total int) {
+	c, ok := r[file]
+	if !ok {
+		c = &fileCoverage{}
+		r[file] = c
+	}
+	c.Covered += covered
+	c.Total += total
+}
+
+// total coverage of the report.
+func (r coverageReport) total() fileCoverage {
+	var t fileCoverage
+	for _, c := range r {
+		t.Covered += c.Covered
+		t.Total += c.Total
+	}
+	return t
+}
+
+// lookup the coverage of a file in the repository. Coverage files often use
+// absolute paths or Go import paths, so the file is matched by suffix. If
+// several files match (e.g. main.go and cmd/x/main.go for main.go), the one
+// with the shortest path is the closest match.
+func (r coverageReport) lookup(path string) (*fileCoverage, bool) {
+	if c, ok := r[path]; ok {
+		return c, true
+	}
+	match := ""
+	for file := range r {
+		if !strings.HasSuffix(file, "/"+path) {
+			continue
+		}
+		if match == "" || len(file) < len(match) || (len(file) == len(match) && file < match) {
+			match = file
+		}
+	}
+	if match == "" {
+		return nil, false
+	}
+	return r[match], true
+}
+
+func (c fileCoverage) percent() float64 {
+	if c.Total == 0 {
+		return 100
+	}
+	return 100 * float64(c.Covered) / float64(c.Total)
+}
+
+// parseCoverage parses a Go cover profile, Cobertura XML or LCOV report. If
+// format is empty, it is detected from the content.
+func parseCoverage(content []byte, format string) (coverageReport, error) {
+	if format == "" {
+		format = detectCoverageFormat(content)
+	}
+	switch strings.ToLower(format) {
+	case "go":
+		return parseGoCoverProfile(content)
+	case "cobertura":
+		return parseCobertura(content)
+	case "lcov":
+		return parseLCOV(content)
+	case "":
+		return nil, errors.New("unknown coverage format")
+	default:
+		return nil, fmt.Errorf("unknown coverage format: %s", format)
+	}
+}
+
+func detectCoverageFormat(content []byte) string {
+	trimmed := bytes.TrimSpace(content)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return "go"
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return "cobertura"
+	case bytes.HasPrefix(trimmed, []byte("TN:")), bytes.HasPrefix(trimmed, []byte("SF:")):
+		return "lcov"
+	}
+	return ""
+}
+
+// parseGoCoverProfile parses lines of the form
+// "name.go:line.column,line.column numberOfStatements count". A block appears
+// once per test binary that covers its package (e.g. with -coverpkg), and is
+// covered if any of its lines has a count.
+func parseGoCoverProfile(content []byte) (coverageReport, error) {
+	type block struct {
+		file       string
+		statements int
+		covered    bool
+	}
+	blocks := make(map[string]*block)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		colon := strings.LastIndex(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("malformed cover profile on line %d", n)
+		}
+		fields := strings.Fields(line[colon+1:])
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("malformed cover profile on line %d", n)
+		}
+		statements, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("malformed cover profile on line %d: %s", n, err)
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("malformed cover profile on line %d: %s", n, err)
+		}
+		key := line[:colon+1] + fields[0]
+		b, ok := blocks[key]
+		if !ok {
+			b = &block{file: line[:colon], statements: statements}
+			blocks[key] = b
+		}
+		b.covered = b.covered || count > 0
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	report := make(coverageReport)
+	for _, b := range blocks {
+		covered := 0
+		if b.covered {
+			covered = b.statements
+		}
+		report.add(b.file, covered, b.statements)
+	}
+	return report, nil
+}
+
+func parseCobertura(content []byte) (coverageReport, error) {
+	var coverage struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Hits int `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"packages>package>classes>class"`
+	}
+	if err := xml.Unmarshal(content, &coverage); err != nil {
+		return nil, fmt.Errorf("malformed cobertura report: %s", err)
+	}
+
+	report := make(coverageReport)
+	for _, class := range coverage.Classes {
+		covered := 0
+		for _, l := range class.Lines {
+			if l.Hits > 0 {
+				covered++
+			}
+		}
+		report.add(class.Filename, covered, len(class.Lines))
+	}
+	return report, nil
+}
+
+func parseLCOV(content []byte) (coverageReport, error) {
+	report := make(coverageReport)
+	var file string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			file = strings.TrimPrefix(line, "SF:")
+			report.add(file, 0, 0)
+		case strings.HasPrefix(line, "DA:"):
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if file == "" || len(fields) < 2 {
+				return nil, fmt.Errorf("malformed lcov report on line %d", n)
+			}
+			hits, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("malformed lcov report on line %d: %s", n, err)
+			}
+			covered := 0
+			if hits > 0 {
+				covered = 1
+			}
+			report.add(file, covered, 1)
+		case line == "end_of_record":
+			file = ""
+		}
+	}
+	return report, scanner.Err()
+}
+
+// coverageComment renders the total coverage and that of the changed files,
+// along with the delta to the baseline if there is one.
+func coverageComment(report, baseline coverageReport, changedFiles []string) string {
+	var b strings.Builder
+	total := report.total()
+	fmt.Fprintf(&b, "### Coverage\n\nTotal: **%.1f%%**", total.percent())
+	if baseline != nil {
+		fmt.Fprintf(&b, " (%s)", formatDelta(total.percent()-baseline.total().percent()))
+	}
+	b.WriteString("\n")
+
+	var rows []string
+	for _, path := range changedFiles {
+		c, ok := report.lookup(path)
+		if !ok {
+			continue
+		}
+		row := fmt.Sprintf("| `%s` | %.1f%% |", tableCell(path), c.percent())
+		if baseline != nil {
+			delta := "new"
+			if base, ok := baseline.lookup(path); ok {
+				delta = formatDelta(c.percent() - base.percent())
+			}
+			row += fmt.Sprintf(" %s |", delta)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) > 0 {
+		sort.Strings(rows)
+		if baseline != nil {
+			b.WriteString("\n| Changed file | Coverage | Delta |\n|---|---|---|\n")
+		} else {
+			b.WriteString("\n| Changed file | Coverage |\n|---|---|\n")
+		}
+		b.WriteString(strings.Join(rows, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func formatDelta(delta float64) string {
+	// Avoid reporting -0.0% for tiny decreases.
+	if delta > -0.05 && delta < 0.05 {
+		return "±0.0%"
+	}
+	return fmt.Sprintf("%+.1f%%", delta)
+}
@@ -0,0 +1,42 @@
+package pr
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+)
+
+var signedOffByRegexp = regexp.MustCompile(`(?m)^Signed-off-by: .* <([^>]+)>\s*$`)
+
+// botEmailRegexp matches the email of commits authored by GitHub Apps, which
+// have no user in the GraphQL API.
+var botEmailRegexp = regexp.MustCompile(`(?i)\[bot\]@users\.noreply\.github\.com$`)
+
+// unsignedCommits returns the short SHAs of the commits that are not signed
+// off by their author, skipping exempt authors.
+func unsignedCommits(commits []models.CommitObject, p *DCOParameters) []string {
+	var offenders []string
+	for _, c := range commits {
+		login, email := c.Author.User.Login, c.Author.Email
+		if p.ExemptBots && botEmailRegexp.MatchString(email) {
+			continue
+		}
+		if (login != "" && containsString(p.ExemptAuthors, login)) || containsString(p.ExemptAuthors, email) {
+			continue
+		}
+		if !isSignedOffBy(c.Message, email) {
+			offenders = append(offenders, shortSHA(c.OID))
+		}
+	}
+	return offenders
+}
+
+func isSignedOffBy(message, email string) bool {
+	for _, match := range signedOffByRegexp.FindAllStringSubmatch(message, -1) {
+		if strings.EqualFold(match[1], email) {
+			return true
+		}
+	}
+	return false
+}
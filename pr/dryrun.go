@@ -0,0 +1,173 @@
+package pr
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+	"github.com/shurcooL/githubv4"
+)
+
+// dryRunPlan collects the changes a put would make with dry_run.
+type dryRunPlan struct {
+	planned []string
+}
+
+func (d *dryRunPlan) add(format string, args ...interface{}) {
+	change := fmt.Sprintf(format, args...)
+	fmt.Fprintf(os.Stderr, "dry run: would %s\n", change)
+	d.planned = append(d.planned, change)
+}
+
+// dryRunGithub passes read-only requests through to Github, and plans all
+// others instead of making them.
+type dryRunGithub struct {
+	models.Github
+	plan *dryRunPlan
+}
+
+func (d dryRunGithub) CreatePullRequest(base, head, title, body string) (int, error) {
+	d.plan.add("open pull request from %s to %s: %q", head, base, title)
+	return 0, nil
+}
+
+func (d dryRunGithub) PostComment(prNumber int, comment string) error {
+	d.plan.add("comment on #%d: %q", prNumber, comment)
+	return nil
+}
+
+func (d dryRunGithub) UpsertComment(prNumber int, marker, comment string) error {
+	d.plan.add("comment on #%d (or edit the previous comment): %q", prNumber, comment)
+	return nil
+}
+
+func (d dryRunGithub) AddLabels(prNumber int, labels []string) error {
+	d.plan.add("add labels to #%d: %s", prNumber, strings.Join(labels, ", "))
+	return nil
+}
+
+func (d dryRunGithub) RemoveLabel(prNumber int, label string) error {
+	d.plan.add("remove label from #%d: %s", prNumber, label)
+	return nil
+}
+
+func (d dryRunGithub) UpdatePullRequestBody(prNumber int, body string) error {
+	d.plan.add("update description of #%d: %q", prNumber, body)
+	return nil
+}
+
+func (d dryRunGithub) UpdateCommitStatus(commitRef, baseContext, statusContext, status, targetURL, description string) error {
+	if baseContext == "" {
+		baseContext = "concourse-ci"
+	}
+	if statusContext == "" {
+		statusContext = "status"
+	}
+	d.plan.add("set status %s/%s to %s on %s", baseContext, statusContext, status, shortSHA(commitRef))
+	return nil
+}
+
+func (d dryRunGithub) DeletePreviousComments(prNumber int) error {
+	d.plan.add("delete previous comments on #%d", prNumber)
+	return nil
+}
+
+func (d dryRunGithub) CreateDeployment(commitRef, environment, description string) (int64, error) {
+	d.plan.add("create deployment of %s to %s", shortSHA(commitRef), environment)
+	return 0, nil
+}
+
+func (d dryRunGithub) CreateDeploymentStatus(id int64, state, environmentURL, logURL, description string) error {
+	if id == 0 {
+		d.plan.add("set status of the new deployment to %s", state)
+	} else {
+		d.plan.add("set status of deployment %d to %s", id, state)
+	}
+	return nil
+}
+
+func (d dryRunGithub) MergePullRequest(prNumber int, headSHA, method, commitTitle, commitMessage string) (string, error) {
+	if method == "" {
+		method = "merge"
+	}
+	d.plan.add("merge #%d at %s using %s", prNumber, shortSHA(headSHA), method)
+	return "", nil
+}
+
+func (d dryRunGithub) DeleteBranch(branch string) error {
+	d.plan.add("delete branch %s", branch)
+	return nil
+}
+
+func (d dryRunGithub) ClosePullRequest(id string) error {
+	d.plan.add("close pull request")
+	return nil
+}
+
+func (d dryRunGithub) ReopenPullRequest(id string) error {
+	d.plan.add("reopen pull request")
+	return nil
+}
+
+func (d dryRunGithub) ConvertPullRequestToDraft(id string) error {
+	d.plan.add("convert pull request to draft")
+	return nil
+}
+
+func (d dryRunGithub) MarkPullRequestReadyForReview(id string) error {
+	d.plan.add("mark pull request as ready for review")
+	return nil
+}
+
+func (d dryRunGithub) EnablePullRequestAutoMerge(id, headSHA, method, commitHeadline, commitBody string) (*models.AutoMergeRequestObject, error) {
+	if method == "" {
+		method = "merge"
+	}
+	d.plan.add("enable auto-merge of %s using %s", shortSHA(headSHA), method)
+	return &models.AutoMergeRequestObject{MergeMethod: githubv4.PullRequestMergeMethod(strings.ToUpper(method))}, nil
+}
+
+func (d dryRunGithub) DisablePullRequestAutoMerge(id string) error {
+	d.plan.add("disable auto-merge")
+	return nil
+}
+
+func (d dryRunGithub) UpdatePullRequestBranch(id, headSHA, method string) (string, error) {
+	if method == "" {
+		method = "merge"
+	}
+	d.plan.add("update branch at %s with the base branch using %s", shortSHA(headSHA), method)
+	return "", nil
+}
+
+func (d dryRunGithub) DispatchWorkflow(workflow, ref string, inputs map[string]string, dispatchID string) (*models.WorkflowRun, error) {
+	d.plan.add("dispatch workflow %s on %s with inputs %v", workflow, ref, inputs)
+	return nil, nil
+}
+
+func (d dryRunGithub) DispatchRepositoryEvent(eventType string, payload map[string]string) error {
+	d.plan.add("dispatch repository event %s with payload %v", eventType, payload)
+	return nil
+}
+
+// dryRunGit plans the commits and pushes of a put instead of making them.
+type dryRunGit struct {
+	models.Git
+	plan *dryRunPlan
+}
+
+func (d dryRunGit) Commit(message, authorName, authorEmail string) (bool, error) {
+	d.plan.add("commit changes as %s <%s>: %q", authorName, authorEmail, message)
+	return true, nil
+}
+
+func (d dryRunGit) Push(uri, branch, expectedSHA string) error {
+	d.plan.add("push to branch %s", branch)
+	return nil
+}
+
+func (d dryRunGit) CherryPick(uri, branch, newBranch string, commits []string) ([]string, error) {
+	d.plan.add("cherry-pick %d commits onto %s as %s", len(commits), branch, newBranch)
+	return nil, nil
+}
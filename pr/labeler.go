@@ -0,0 +1,60 @@
+package pr
+
+import (
+	"sort"
+
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+	"github.com/cloudfoundry-community/github-pr-instances-resource/prlist"
+)
+
+// labelChanges returns the labels to add to and remove from a pull request, so
+// that the labels managed by the labeler reflect the modified files and the
+// number of changed lines. Labels that are not managed are left untouched.
+func labelChanges(p *LabelerParameters, files []string, changedLines int, current []models.LabelObject) ([]string, []string, error) {
+	wanted := make(map[string]bool)
+	for label, patterns := range p.Paths {
+		for _, pattern := range patterns {
+			matches, err := prlist.FilterPath(files, pattern)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(matches) > 0 {
+				wanted[label] = true
+				break
+			}
+		}
+	}
+
+	// The size label is the one with the largest threshold that is reached.
+	size, threshold := "", -1
+	for label, min := range p.Sizes {
+		if changedLines >= min && (min > threshold || (min == threshold && label < size)) {
+			size, threshold = label, min
+		}
+	}
+	if size != "" {
+		wanted[size] = true
+	}
+
+	has := make(map[string]bool, len(current))
+	for _, l := range current {
+		has[l.Name] = true
+	}
+
+	var add, remove []string
+	for label := range wanted {
+		if !has[label] {
+			add = append(add, label)
+		}
+	}
+	for label := range has {
+		_, isPath := p.Paths[label]
+		_, isSize := p.Sizes[label]
+		if (isPath || isSize) && !wanted[label] {
+			remove = append(remove, label)
+		}
+	}
+	sort.Strings(add)
+	sort.Strings(remove)
+	return add, remove, nil
+}
@@ -2,8 +2,10 @@ package pr
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+	"github.com/cloudfoundry-community/github-pr-instances-resource/prlist"
 )
 
 // Source represents the configuration for the resource.
@@ -16,6 +18,13 @@ type Source struct {
 	Paths         []string `json:"paths"`
 	IgnorePaths   []string `json:"ignore_paths"`
 	DisableCISkip bool     `json:"disable_ci_skip"`
+	// Skip commits that were pushed by a put of this resource.
+	IgnorePushedCommits bool `json:"ignore_pushed_commits"`
+	// Do not emit versions while the pull request has one of these labels.
+	IgnoreLabels []string `json:"ignore_labels"`
+	// Only emit commits of untrusted forks that a maintainer approved.
+	RequireOkToTest bool   `json:"require_ok_to_test"`
+	OkToTestLabel   string `json:"ok_to_test_label"`
 }
 
 // Validate the source configuration.
@@ -33,9 +42,22 @@ func (s *Source) Validate() error {
 	if isHostingEndpointEnabled != isV3EndpointEnabled || isV3EndpointEnabled != isV4EndpointEnabled {
 		return errors.New("if any of hosting_endpoint, v3_endpoint, or v4_endpoint are set, all of them must be set")
 	}
+	for _, pattern := range s.IgnoreLabels {
+		if _, err := prlist.MatchPattern(pattern, ""); err != nil {
+			return fmt.Errorf("invalid ignore_labels pattern %q: %s", pattern, err)
+		}
+	}
 	return nil
 }
 
+// okToTestLabel returns the label that approves commits for testing.
+func (s *Source) okToTestLabel() string {
+	if s.OkToTestLabel != "" {
+		return s.OkToTestLabel
+	}
+	return models.DefaultOkToTestLabel
+}
+
 type Version struct {
 	Ref string `json:"ref"`
 }
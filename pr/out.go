@@ -1,17 +1,35 @@
 package pr
 
 import (
+	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
-	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+	"github.com/shurcooL/githubv4"
 )
 
-func Put(request PutRequest, github models.Github, inputDir string) (*PutResponse, error) {
+func Put(request PutRequest, github models.Github, git models.Git, inputDir string) (*PutResponse, error) {
+	// Statuses written to a file by a task are validated along with the other parameters.
+	if p := request.Params; p.StatusesFile != "" {
+		content, err := ioutil.ReadFile(filepath.Join(inputDir, p.StatusesFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read statuses file: %v", err)
+		}
+		var statuses []StatusParameters
+		if err := json.Unmarshal(content, &statuses); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal statuses file: %v", err)
+		}
+		request.Params.Statuses = append(request.Params.Statuses, statuses...)
+	}
+
 	if err := request.Params.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid parameters: %s", err)
 	}
@@ -38,30 +56,624 @@ func Put(request PutRequest, github models.Github, inputDir string) (*PutRespons
 		return nil, fmt.Errorf("failed to unmarshal metadata from file: %v", err)
 	}
 
+	// Record the outcome of each action, so that it is known what was done
+	// when one of them fails.
+	actions := actionRunner{metadata: &metadata, continueOnError: request.Params.ContinueOnError}
+
+	// Plan changes against the live pull request without making them.
+	if request.Params.DryRun {
+		actions.plan = &dryRunPlan{}
+		github = dryRunGithub{Github: github, plan: actions.plan}
+		git = dryRunGit{Git: git, plan: actions.plan}
+		metadata.Add("dry_run", "true")
+	}
+
+	// Wait for other checks on the commit to complete if specified
+	if p := request.Params.WaitForChecks; p != nil {
+		if err := actions.run("wait_for_checks", func() error {
+			if actions.plan != nil {
+				actions.plan.add("wait for checks: %s", strings.Join(p.Contexts, ", "))
+				return nil
+			}
+			// The states explain a failure or timeout as well.
+			states, err := waitForChecks(github, version.Ref, p)
+			if states != nil {
+				for _, name := range p.Contexts {
+					metadata.Add("check_"+name, states[name])
+				}
+			}
+			return err
+		}); err != nil {
+			return nil, err
+		}
+	}
+
 	// Set status if specified
 	if p := request.Params; p.Status != "" {
-		description := p.Description
+		if err := actions.run("status", func() error {
+			description := p.Description
 
-		if err := github.UpdateCommitStatus(version.Ref, p.BaseContext, safeExpandEnv(p.Context), p.Status, safeExpandEnv(p.TargetURL), description); err != nil {
-			return nil, fmt.Errorf("failed to set status: %v", err)
+			if err := github.UpdateCommitStatus(version.Ref, p.BaseContext, models.SafeExpandEnv(p.Context), p.Status, models.SafeExpandEnv(p.TargetURL), description); err != nil {
+				return fmt.Errorf("failed to set status: %v", err)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if statuses := request.Params.Statuses; len(statuses) > 0 {
+		if err := actions.run("statuses", func() error {
+			for _, st := range statuses {
+				if err := github.UpdateCommitStatus(version.Ref, request.Params.BaseContext, models.SafeExpandEnv(st.Context), st.State, models.SafeExpandEnv(st.TargetURL), st.Description); err != nil {
+					return fmt.Errorf("failed to set status %s: %v", st.Context, err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
 		}
 	}
 
 	prNumber := request.Source.Number
 
-	// Delete previous comments if specified
-	if request.Params.DeletePreviousComments {
-		err = github.DeletePreviousComments(prNumber)
+	// Check whether new commits have been pushed since the version was fetched,
+	// in which case comments would look like they are about the current head.
+	// Statuses are unaffected, as they are tied to the commit of the version.
+	var superseded bool
+	if request.Params.Superseded != "" {
+		head, err := github.GetPullRequestHeadSHA(prNumber)
 		if err != nil {
-			return nil, fmt.Errorf("failed to delete previous comments: %v", err)
+			return nil, fmt.Errorf("failed to get head of pull request: %v", err)
+		}
+		superseded = head != version.Ref
+		metadata.Add("superseded", strconv.FormatBool(superseded))
+	}
+	skipComments := superseded && strings.ToLower(request.Params.Superseded) == "skip"
+	var annotation string
+	if superseded && strings.ToLower(request.Params.Superseded) == "annotate" {
+		annotation = fmt.Sprintf("> Results for older commit %s\n\n", shortSHA(version.Ref))
+	}
+
+	// Delete previous comments if specified, unless they might be about a newer commit
+	if request.Params.DeletePreviousComments && !superseded {
+		if err := actions.run("delete_previous_comments", func() error {
+			if err := github.DeletePreviousComments(prNumber); err != nil {
+				return fmt.Errorf("failed to delete previous comments: %v", err)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
 		}
 	}
 
 	// Set comment if specified
-	if p := request.Params; p.Comment != "" {
-		err = github.PostComment(prNumber, safeExpandEnv(p.Comment))
+	if p := request.Params; p.Comment != "" && !skipComments {
+		if err := actions.run("comment", func() error {
+			// The marker lets a retry of the put edit the comment instead of posting it twice.
+			comment := models.SafeExpandEnv(p.Comment)
+			if err := github.UpsertComment(prNumber, commentMarker(version.Ref, comment), annotation+comment); err != nil {
+				return fmt.Errorf("failed to post comment: %v", err)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Report coverage if specified
+	if p := request.Params.Coverage; p != nil {
+		if err := actions.run("coverage", func() error {
+			content, err := ioutil.ReadFile(filepath.Join(inputDir, p.File))
+			if err != nil {
+				return fmt.Errorf("failed to read coverage file: %v", err)
+			}
+			report, err := parseCoverage(content, p.Format)
+			if err != nil {
+				return fmt.Errorf("failed to parse coverage file: %v", err)
+			}
+
+			var baseline coverageReport
+			if p.BaselineFile != "" {
+				content, err := ioutil.ReadFile(filepath.Join(inputDir, p.BaselineFile))
+				if err != nil {
+					return fmt.Errorf("failed to read baseline coverage file: %v", err)
+				}
+				if baseline, err = parseCoverage(content, p.Format); err != nil {
+					return fmt.Errorf("failed to parse baseline coverage file: %v", err)
+				}
+			}
+
+			total := report.total().percent()
+			description := fmt.Sprintf("%.1f%% covered", total)
+			metadata.Add("coverage", fmt.Sprintf("%.1f", total))
+			if baseline != nil {
+				delta := formatDelta(total - baseline.total().percent())
+				description = fmt.Sprintf("%.1f%% covered (%s)", total, delta)
+				metadata.Add("coverage_delta", delta)
+			}
+			state := "success"
+			if p.Threshold > 0 && total < p.Threshold {
+				state = "failure"
+				description += fmt.Sprintf(", below the threshold of %g%%", p.Threshold)
+			}
+
+			context := p.Context
+			if context == "" {
+				context = "coverage"
+			}
+			if err := github.UpdateCommitStatus(version.Ref, request.Params.BaseContext, context, state, "", truncateDescription(description)); err != nil {
+				return fmt.Errorf("failed to set coverage status: %v", err)
+			}
+
+			if !skipComments {
+				files, err := github.ListModifiedFiles(prNumber)
+				if err != nil {
+					return fmt.Errorf("failed to list modified files: %v", err)
+				}
+				if err := github.UpsertComment(prNumber, coverageCommentMarker, annotation+coverageComment(report, baseline, files)); err != nil {
+					return fmt.Errorf("failed to post coverage comment: %v", err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Summarize the statuses and checks on the commit if specified
+	if p := request.Params.StatusSummary; p != nil && !skipComments {
+		if err := actions.run("status_summary", func() error {
+			checks, err := github.ListCommitChecks(version.Ref)
+			if err != nil {
+				return fmt.Errorf("failed to list checks: %v", err)
+			}
+			if err := github.UpsertComment(prNumber, statusSummaryMarker, annotation+statusSummaryComment(p.Title, version.Ref, checks)); err != nil {
+				return fmt.Errorf("failed to post status summary: %v", err)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update a section of the pull request description if specified
+	if p := request.Params.BodySection; p != nil && !skipComments {
+		if err := actions.run("body_section", func() error {
+			content := p.Content
+			if p.ContentFile != "" {
+				b, err := ioutil.ReadFile(filepath.Join(inputDir, p.ContentFile))
+				if err != nil {
+					return fmt.Errorf("failed to read body section content: %v", err)
+				}
+				content = string(b)
+			}
+
+			// Read the body as late as possible, so that we are less likely to
+			// overwrite concurrent edits by the author.
+			body, err := github.GetPullRequestBody(prNumber)
+			if err != nil {
+				return fmt.Errorf("failed to get pull request body: %v", err)
+			}
+			updated := replaceBodySection(body, p.Name, annotation+models.SafeExpandMetadata(content, metadata))
+			if updated != body {
+				if err := github.UpdatePullRequestBody(prNumber, updated); err != nil {
+					return fmt.Errorf("failed to update pull request body: %v", err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create a deployment or update its status if specified
+	if p := request.Params.Deployment; p != nil {
+		if err := actions.run("deployment", func() error {
+			environment := p.Environment
+			if environment == "" {
+				environment = "pr-$pr"
+			}
+			environment = models.SafeExpandMetadata(environment, metadata)
+			description := models.SafeExpandMetadata(p.Description, metadata)
+
+			if strings.ToLower(p.State) == "inactive" {
+				// Deactivate every deployment to the environment, e.g. once the PR is closed.
+				ids, err := github.ListDeployments("", environment)
+				if err != nil {
+					return fmt.Errorf("failed to list deployments: %v", err)
+				}
+				for _, id := range ids {
+					if err := github.CreateDeploymentStatus(id, p.State, "", models.SafeExpandEnv(p.LogURL), description); err != nil {
+						return fmt.Errorf("failed to deactivate deployment %d: %v", id, err)
+					}
+				}
+			} else {
+				// Reuse the deployment created by a previous put for the same version
+				// and environment (the latest, if there are several).
+				ids, err := github.ListDeployments(version.Ref, environment)
+				if err != nil {
+					return fmt.Errorf("failed to list deployments: %v", err)
+				}
+				var id int64
+				if len(ids) > 0 {
+					id = ids[0]
+				} else {
+					id, err = github.CreateDeployment(version.Ref, environment, description)
+					if err != nil {
+						return fmt.Errorf("failed to create deployment: %v", err)
+					}
+				}
+
+				if err := github.CreateDeploymentStatus(id, p.State, models.SafeExpandMetadata(p.EnvironmentURL, metadata), models.SafeExpandEnv(p.LogURL), description); err != nil {
+					return fmt.Errorf("failed to set deployment status: %v", err)
+				}
+				metadata.Add("deployment_id", strconv.FormatInt(id, 10))
+			}
+			metadata.Add("deployment_environment", environment)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Trigger a GitHub Actions workflow if specified
+	if p := request.Params.WorkflowDispatch; p != nil {
+		if err := actions.run("workflow_dispatch", func() error {
+			ref := p.Ref
+			if ref == "" {
+				// Run the workflow as defined on the base branch, rather than
+				// code from the pull request (which may not even be in this repository).
+				ref = "$base_name"
+			}
+			inputs := expandMetadataMap(p.Inputs, metadata)
+			var dispatchID string
+			if p.IDInput != "" {
+				// Passed to the workflow so that we can recognize its run.
+				id, err := newDispatchID()
+				if err != nil {
+					return err
+				}
+				dispatchID = id
+				if inputs == nil {
+					inputs = make(map[string]string, 1)
+				}
+				inputs[p.IDInput] = dispatchID
+			}
+			run, err := github.DispatchWorkflow(p.Workflow, models.SafeExpandMetadata(ref, metadata), inputs, dispatchID)
+			if err != nil {
+				return fmt.Errorf("failed to dispatch workflow: %v", err)
+			}
+			if run != nil {
+				metadata.Add("workflow_run_id", strconv.FormatInt(run.ID, 10))
+				metadata.Add("workflow_run_url", run.URL)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Send a repository_dispatch event if specified
+	if p := request.Params.RepositoryDispatch; p != nil {
+		if err := actions.run("repository_dispatch", func() error {
+			if err := github.DispatchRepositoryEvent(p.EventType, expandMetadataMap(p.ClientPayload, metadata)); err != nil {
+				return fmt.Errorf("failed to dispatch repository event: %v", err)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// The pull request itself is only needed by some of the actions below.
+	var pull *models.PullRequest
+	if p := request.Params; p.Push != nil || p.State != "" || p.Draft != nil || p.AutoMerge != nil || p.DisableAutoMerge || p.Merge != nil || p.Backport != nil || p.UpdateBranch != nil || p.SemanticTitle != nil || p.Labeler != nil {
+		pull, err = github.GetPullRequest(prNumber, version.Ref)
 		if err != nil {
-			return nil, fmt.Errorf("failed to post comment: %v", err)
+			return nil, fmt.Errorf("failed to retrieve pull request: %v", err)
+		}
+	}
+
+	// Validate the title of the pull request if specified
+	if p := request.Params.SemanticTitle; p != nil {
+		if err := actions.run("semantic_title", func() error {
+			context := p.Context
+			if context == "" {
+				context = "semantic-title"
+			}
+			state, description := "success", "Title follows Conventional Commits"
+			if problem := checkSemanticTitle(pull.Title, p); problem != "" {
+				state, description = "failure", truncateDescription(problem)
+			}
+			if err := github.UpdateCommitStatus(version.Ref, request.Params.BaseContext, context, state, "", description); err != nil {
+				return fmt.Errorf("failed to set semantic title status: %v", err)
+			}
+			metadata.Add("semantic_title", state)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update labels to reflect the current diff if specified
+	if p := request.Params.Labeler; p != nil {
+		if err := actions.run("labeler", func() error {
+			files, err := github.ListModifiedFiles(prNumber)
+			if err != nil {
+				return fmt.Errorf("failed to list modified files: %v", err)
+			}
+			add, remove, err := labelChanges(p, files, pull.Additions+pull.Deletions, pull.Labels)
+			if err != nil {
+				return fmt.Errorf("failed to match paths: %v", err)
+			}
+			if len(add) > 0 {
+				if err := github.AddLabels(prNumber, add); err != nil {
+					return fmt.Errorf("failed to add labels: %v", err)
+				}
+			}
+			for _, l := range remove {
+				if err := github.RemoveLabel(prNumber, l); err != nil {
+					return fmt.Errorf("failed to remove label: %v", err)
+				}
+			}
+			metadata.Add("labels_added", strings.Join(add, ", "))
+			metadata.Add("labels_removed", strings.Join(remove, ", "))
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check that all commits are signed off if specified
+	if p := request.Params.DCO; p != nil {
+		if err := actions.run("dco", func() error {
+			commits, err := github.ListPullRequestCommits(prNumber)
+			if err != nil {
+				return fmt.Errorf("failed to list commits: %v", err)
+			}
+			context := p.Context
+			if context == "" {
+				context = "dco"
+			}
+			state, description := "success", "All commits are signed off"
+			if offenders := unsignedCommits(commits, p); len(offenders) > 0 {
+				state = "failure"
+				description = truncateDescription(fmt.Sprintf("Commits without a Signed-off-by of their author: %s", strings.Join(offenders, ", ")))
+				metadata.Add("dco_offenders", strings.Join(offenders, ", "))
+			}
+			if err := github.UpdateCommitStatus(version.Ref, request.Params.BaseContext, context, state, "", description); err != nil {
+				return fmt.Errorf("failed to set dco status: %v", err)
+			}
+			metadata.Add("dco", state)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Push changes in the repository back to the head branch if specified
+	if p := request.Params.Push; p != nil {
+		if err := actions.run("push", func() error {
+			uri := pull.Repository.URL
+			if pull.IsCrossRepository {
+				if !pull.MaintainerCanModify {
+					return errors.New("failed to push: the author of the pull request does not allow maintainers to modify the head branch")
+				}
+				uri = pull.HeadRepository.URL
+			}
+
+			// Never push on top of commits that were not tested.
+			headSHA, err := github.GetPullRequestHeadSHA(prNumber)
+			if err != nil {
+				return fmt.Errorf("failed to get head of pull request: %v", err)
+			}
+			if headSHA != version.Ref {
+				return fmt.Errorf("failed to push: head of pull request has moved from %s to %s", shortSHA(version.Ref), shortSHA(headSHA))
+			}
+
+			authorName, authorEmail := p.AuthorName, p.AuthorEmail
+			if authorName == "" {
+				authorName = "concourse-ci"
+			}
+			if authorEmail == "" {
+				authorEmail = "concourse@local"
+			}
+			message := models.SafeExpandMetadata(p.Message, metadata) + "\n\n" + models.PushedCommitTrailer
+			committed, err := git.Commit(message, authorName, authorEmail)
+			if err != nil {
+				return fmt.Errorf("failed to commit changes: %v", err)
+			}
+			// A dry run commits nothing, so there is no pushed commit to record.
+			if committed && actions.plan != nil {
+				return git.Push(uri, pull.HeadRefName, version.Ref)
+			}
+			if committed {
+				sha, err := git.RevParse("HEAD")
+				if err != nil {
+					return fmt.Errorf("failed to get pushed commit: %v", err)
+				}
+				if err := git.Push(uri, pull.HeadRefName, version.Ref); err != nil {
+					return fmt.Errorf("failed to push changes: %v", err)
+				}
+				metadata.Add("pushed_sha", sha)
+			} else {
+				metadata.Add("pushed_sha", "none (nothing to commit)")
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update the head branch with the base branch if specified
+	if p := request.Params.UpdateBranch; p != nil {
+		if err := actions.run("update_branch", func() error {
+			behind, err := github.CountCommitsBehind(pull.BaseRefName, version.Ref)
+			if err != nil {
+				return fmt.Errorf("failed to compare with base branch: %v", err)
+			}
+			if behind == 0 {
+				metadata.Add("update_branch", "up-to-date")
+			} else {
+				// Only update the commit that was fetched by get, so that newer
+				// commits are not built on without being tested first.
+				sha, err := github.UpdatePullRequestBranch(pull.ID, version.Ref, p.Method)
+				switch {
+				case err == models.ErrMergeConflict:
+					metadata.Add("update_branch", "conflict")
+				case err != nil:
+					return fmt.Errorf("failed to update branch: %v", err)
+				default:
+					metadata.Add("update_branch", "updated")
+					metadata.Add("update_branch_sha", sha)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Close or reopen the pull request if specified
+	if state := strings.ToLower(request.Params.State); state != "" {
+		if err := actions.run("state", func() error {
+			switch {
+			case state == "closed" && pull.State == githubv4.PullRequestStateOpen:
+				if err := github.ClosePullRequest(pull.ID); err != nil {
+					return fmt.Errorf("failed to close pull request: %v", err)
+				}
+			case state == "open" && pull.State == githubv4.PullRequestStateClosed:
+				if err := github.ReopenPullRequest(pull.ID); err != nil {
+					return fmt.Errorf("failed to reopen pull request: %v", err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Convert to or from a draft if specified
+	if draft := request.Params.Draft; draft != nil && *draft != pull.IsDraft {
+		if err := actions.run("draft", func() error {
+			if *draft {
+				err = github.ConvertPullRequestToDraft(pull.ID)
+			} else {
+				err = github.MarkPullRequestReadyForReview(pull.ID)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to update draft state: %v", err)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Enable or disable auto-merge if specified
+	if p := request.Params.AutoMerge; p != nil {
+		if err := actions.run("auto_merge", func() error {
+			// As with merge, only the commit that was fetched by get may be merged.
+			autoMerge, err := github.EnablePullRequestAutoMerge(pull.ID, version.Ref, p.Method, models.SafeExpandMetadata(p.CommitHeadline, metadata), models.SafeExpandMetadata(p.CommitBody, metadata))
+			if err != nil {
+				return fmt.Errorf("failed to enable auto-merge: %v", err)
+			}
+			metadata.Add("auto_merge", "enabled")
+			metadata.Add("auto_merge_method", strings.ToLower(string(autoMerge.MergeMethod)))
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if request.Params.DisableAutoMerge {
+		if err := actions.run("disable_auto_merge", func() error {
+			if err := github.DisablePullRequestAutoMerge(pull.ID); err != nil {
+				return fmt.Errorf("failed to disable auto-merge: %v", err)
+			}
+			metadata.Add("auto_merge", "disabled")
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Merge the pull request if specified
+	var merged bool
+	if p := request.Params.Merge; p != nil {
+		if err := actions.run("merge", func() error {
+			// A retry of the put should not fail because the first attempt merged.
+			if pull.State == githubv4.PullRequestStateMerged {
+				metadata.Add("merge_sha", "already merged")
+			} else {
+				// Only merge the commit that was fetched by get, i.e. the one we tested.
+				sha, err := github.MergePullRequest(prNumber, version.Ref, strings.ToLower(p.Method), models.SafeExpandMetadata(p.CommitTitle, metadata), models.SafeExpandMetadata(p.CommitMessage, metadata))
+				if err != nil {
+					return fmt.Errorf("failed to merge pull request: %v", err)
+				}
+				// Likewise, a dry run has no merge commit to record.
+				if actions.plan == nil {
+					metadata.Add("merge_sha", sha)
+				}
+			}
+			merged = true
+
+			// Branches of forks can not be deleted through the base repository.
+			if p.DeleteBranch && !pull.IsCrossRepository {
+				if err := github.DeleteBranch(pull.HeadRefName); err != nil {
+					return fmt.Errorf("failed to delete head branch: %v", err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Backport the pull request if specified
+	if p := request.Params.Backport; p != nil {
+		if err := actions.run("backport", func() error {
+			// The pull request may have been merged by this put.
+			if pull.State != githubv4.PullRequestStateMerged && !merged {
+				return errors.New("failed to backport: pull request is not merged")
+			}
+
+			prefix := p.LabelPrefix
+			if prefix == "" {
+				prefix = "backport/"
+			}
+			branches := p.Branches
+			for _, l := range pull.Labels {
+				if branch := strings.TrimPrefix(l.Name, prefix); branch != l.Name && branch != "" && !containsString(branches, branch) {
+					branches = append(branches, branch)
+				}
+			}
+
+			if len(branches) > 0 {
+				commits, err := github.ListPullRequestCommits(prNumber)
+				if err != nil {
+					return fmt.Errorf("failed to list commits: %v", err)
+				}
+				shas := make([]string, len(commits))
+				for i, c := range commits {
+					shas[i] = c.OID
+				}
+
+				// A failure for one branch should not prevent the other backports,
+				// so the outcome for each branch is reported on the pull request.
+				var results []string
+				for _, branch := range branches {
+					result := backport(github, git, pull, branch, shas)
+					metadata.Add("backport_"+branch, result)
+					results = append(results, fmt.Sprintf("- `%s`: %s", branch, result))
+				}
+				// The marker lets a retry of the put edit the comment instead of posting it twice.
+				if err := github.UpsertComment(prNumber, backportCommentMarker, "Backport results:\n\n"+strings.Join(results, "\n")); err != nil {
+					return fmt.Errorf("failed to post comment: %v", err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
 		}
 	}
 
@@ -82,44 +694,454 @@ type PutResponse struct {
 }
 
 type PutParameters struct {
-	Path                   string `json:"path"`
-	BaseContext            string `json:"base_context"`
-	Context                string `json:"context"`
-	TargetURL              string `json:"target_url"`
-	Description            string `json:"description"`
-	Status                 string `json:"status"`
-	Comment                string `json:"comment"`
-	DeletePreviousComments bool   `json:"delete_previous_comments"`
+	Path                   string                        `json:"path"`
+	DryRun                 bool                          `json:"dry_run"`
+	ContinueOnError        bool                          `json:"continue_on_error"`
+	BaseContext            string                        `json:"base_context"`
+	Context                string                        `json:"context"`
+	TargetURL              string                        `json:"target_url"`
+	Description            string                        `json:"description"`
+	Status                 string                        `json:"status"`
+	WaitForChecks          *WaitForChecksParameters      `json:"wait_for_checks"`
+	Statuses               []StatusParameters            `json:"statuses"`
+	StatusesFile           string                        `json:"statuses_file"`
+	Comment                string                        `json:"comment"`
+	DeletePreviousComments bool                          `json:"delete_previous_comments"`
+	Superseded             string                        `json:"superseded"`
+	BodySection            *BodySectionParameters        `json:"body_section"`
+	Deployment             *DeploymentParameters         `json:"deployment"`
+	WorkflowDispatch       *WorkflowDispatchParameters   `json:"workflow_dispatch"`
+	RepositoryDispatch     *RepositoryDispatchParameters `json:"repository_dispatch"`
+	Push                   *PushParameters               `json:"push"`
+	State                  string                        `json:"state"`
+	Draft                  *bool                         `json:"draft"`
+	AutoMerge              *AutoMergeParameters          `json:"auto_merge"`
+	DisableAutoMerge       bool                          `json:"disable_auto_merge"`
+	Merge                  *MergeParameters              `json:"merge"`
+	Backport               *BackportParameters           `json:"backport"`
+	UpdateBranch           *UpdateBranchParameters       `json:"update_branch"`
+	SemanticTitle          *SemanticTitleParameters      `json:"semantic_title"`
+	DCO                    *DCOParameters                `json:"dco"`
+	Coverage               *CoverageParameters           `json:"coverage"`
+	Labeler                *LabelerParameters            `json:"labeler"`
+	StatusSummary          *StatusSummaryParameters      `json:"status_summary"`
+}
+
+type WaitForChecksParameters struct {
+	Contexts []string `json:"contexts"`
+	Timeout  string   `json:"timeout"`
+	Interval string   `json:"interval"`
+}
+
+type StatusParameters struct {
+	Context     string `json:"context"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	TargetURL   string `json:"target_url"`
+}
+
+type BodySectionParameters struct {
+	Name        string `json:"name"`
+	Content     string `json:"content"`
+	ContentFile string `json:"content_file"`
+}
+
+type DeploymentParameters struct {
+	Environment    string `json:"environment"`
+	State          string `json:"state"`
+	EnvironmentURL string `json:"environment_url"`
+	LogURL         string `json:"log_url"`
+	Description    string `json:"description"`
+}
+
+type WorkflowDispatchParameters struct {
+	Workflow string            `json:"workflow"`
+	Ref      string            `json:"ref"`
+	Inputs   map[string]string `json:"inputs"`
+	IDInput  string            `json:"id_input"`
+}
+
+type RepositoryDispatchParameters struct {
+	EventType     string            `json:"event_type"`
+	ClientPayload map[string]string `json:"client_payload"`
+}
+
+type PushParameters struct {
+	Repository  string `json:"repository"`
+	Message     string `json:"message"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+}
+
+type AutoMergeParameters struct {
+	Method         string `json:"method"`
+	CommitHeadline string `json:"commit_headline"`
+	CommitBody     string `json:"commit_body"`
+}
+
+type MergeParameters struct {
+	Method        string `json:"method"`
+	CommitTitle   string `json:"commit_title"`
+	CommitMessage string `json:"commit_message"`
+	DeleteBranch  bool   `json:"delete_branch"`
+}
+
+type SemanticTitleParameters struct {
+	Context      string   `json:"context"`
+	Types        []string `json:"types"`
+	Scopes       []string `json:"scopes"`
+	RequireScope bool     `json:"require_scope"`
+	Pattern      string   `json:"pattern"`
+}
+
+type StatusSummaryParameters struct {
+	Title string `json:"title"`
+}
+
+type LabelerParameters struct {
+	Paths map[string][]string `json:"paths"`
+	Sizes map[string]int      `json:"sizes"`
+}
+
+type CoverageParameters struct {
+	File         string  `json:"file"`
+	BaselineFile string  `json:"baseline_file"`
+	Format       string  `json:"format"`
+	Threshold    float64 `json:"threshold"`
+	Context      string  `json:"context"`
+}
+
+type DCOParameters struct {
+	Context       string   `json:"context"`
+	ExemptBots    bool     `json:"exempt_bots"`
+	ExemptAuthors []string `json:"exempt_authors"`
+}
+
+type UpdateBranchParameters struct {
+	Method string `json:"method"`
+}
+
+type BackportParameters struct {
+	Branches    []string `json:"branches"`
+	LabelPrefix string   `json:"label_prefix"`
 }
 
 func (p *PutParameters) Validate() error {
-	if p.Status == "" {
-		return nil
+	switch strings.ToLower(p.State) {
+	case "", "open":
+	case "closed":
+		if p.Merge != nil {
+			return errors.New("can not both close and merge a pull request")
+		}
+	default:
+		return fmt.Errorf("unknown state: %s", p.State)
+	}
+
+	switch strings.ToLower(p.Superseded) {
+	case "", "skip", "annotate":
+	default:
+		return fmt.Errorf("unknown superseded behaviour: %s", p.Superseded)
+	}
+
+	if p.BodySection != nil && p.BodySection.Content != "" && p.BodySection.ContentFile != "" {
+		return errors.New("only one of body_section.content and body_section.content_file can be set")
+	}
+
+	if p.Deployment != nil {
+		switch strings.ToLower(p.Deployment.State) {
+		case "queued", "pending", "in_progress", "success", "failure", "error", "inactive":
+		default:
+			return fmt.Errorf("unknown deployment state: %s", p.Deployment.State)
+		}
+	}
+
+	if l := p.Labeler; l != nil {
+		for label, patterns := range l.Paths {
+			for _, pattern := range patterns {
+				if _, err := filepath.Match(pattern, ""); err != nil {
+					return fmt.Errorf("invalid labeler pattern for %s: %s", label, err)
+				}
+			}
+		}
+		for label, size := range l.Sizes {
+			if size < 0 {
+				return fmt.Errorf("invalid labeler size for %s: %d", label, size)
+			}
+			if _, ok := l.Paths[label]; ok {
+				return fmt.Errorf("label %s can not be both a path and a size label", label)
+			}
+		}
+	}
+
+	if c := p.Coverage; c != nil {
+		if c.File == "" {
+			return errors.New("coverage.file must be set")
+		}
+		switch strings.ToLower(c.Format) {
+		case "", "go", "cobertura", "lcov":
+		default:
+			return fmt.Errorf("unknown coverage format: %s", c.Format)
+		}
+		if c.Threshold < 0 || c.Threshold > 100 {
+			return fmt.Errorf("coverage.threshold must be between 0 and 100: %g", c.Threshold)
+		}
+	}
+
+	if p.SemanticTitle != nil && p.SemanticTitle.Pattern != "" {
+		if _, err := regexp.Compile(p.SemanticTitle.Pattern); err != nil {
+			return fmt.Errorf("invalid semantic_title.pattern: %s", err)
+		}
+	}
+
+	if p.WorkflowDispatch != nil && p.WorkflowDispatch.Workflow == "" {
+		return errors.New("workflow_dispatch.workflow must be set")
+	}
+
+	if p.RepositoryDispatch != nil && p.RepositoryDispatch.EventType == "" {
+		return errors.New("repository_dispatch.event_type must be set")
+	}
+
+	if p.Push != nil {
+		if p.Push.Repository == "" {
+			return errors.New("push.repository must be set")
+		}
+		if p.Push.Message == "" {
+			return errors.New("push.message must be set")
+		}
+		// The pushed commit would change the head, so the tested commit could no longer be merged.
+		if p.Merge != nil || p.AutoMerge != nil {
+			return errors.New("can not both push to and merge a pull request")
+		}
 	}
-	var allowedStatus bool
 
-	status := strings.ToLower(p.Status)
-	allowed := []string{"success", "pending", "failure", "error"}
+	if p.AutoMerge != nil {
+		if p.DisableAutoMerge {
+			return errors.New("can not both enable and disable auto-merge")
+		}
+		if !isMergeMethod(p.AutoMerge.Method) {
+			return fmt.Errorf("unknown auto-merge method: %s", p.AutoMerge.Method)
+		}
+	}
 
-	for _, a := range allowed {
-		if status == a {
-			allowedStatus = true
+	if p.UpdateBranch != nil {
+		switch strings.ToLower(p.UpdateBranch.Method) {
+		case "", "merge", "rebase":
+		default:
+			return fmt.Errorf("unknown update_branch method: %s", p.UpdateBranch.Method)
+		}
+		// Updating the branch changes the head, so the tested commit could no longer be merged.
+		if p.Merge != nil || p.AutoMerge != nil || p.Push != nil {
+			return errors.New("update_branch can not be combined with push, merge or auto_merge")
 		}
 	}
 
-	if !allowedStatus {
+	if p.Merge != nil && !isMergeMethod(p.Merge.Method) {
+		return fmt.Errorf("unknown merge method: %s", p.Merge.Method)
+	}
+
+	if w := p.WaitForChecks; w != nil {
+		if len(w.Contexts) == 0 {
+			return errors.New("wait_for_checks.contexts must be set")
+		}
+		if _, err := parseDuration(w.Timeout, 0); err != nil {
+			return fmt.Errorf("invalid wait_for_checks.timeout: %s", err)
+		}
+		if _, err := parseDuration(w.Interval, 0); err != nil {
+			return fmt.Errorf("invalid wait_for_checks.interval: %s", err)
+		}
+	}
+
+	if p.Status != "" && !models.IsValidStatus(p.Status) {
 		return fmt.Errorf("unknown status: %s", p.Status)
 	}
 
+	for i, st := range p.Statuses {
+		if st.Context == "" {
+			return fmt.Errorf("statuses[%d]: context must be set", i)
+		}
+		if !models.IsValidStatus(st.State) {
+			return fmt.Errorf("statuses[%d]: unknown state: %s", i, st.State)
+		}
+	}
+
 	return nil
 }
 
-func safeExpandEnv(s string) string {
-	return os.Expand(s, func(v string) string {
-		switch v {
-		case "BUILD_ID", "BUILD_NAME", "BUILD_JOB_NAME", "BUILD_PIPELINE_NAME", "BUILD_TEAM_NAME", "ATC_EXTERNAL_URL":
-			return os.Getenv(v)
+// replaceBodySection replaces the content between the (hidden) markers of the
+// named section in body, or appends the section if body does not contain it.
+func replaceBodySection(body, name, content string) string {
+	if name == "" {
+		name = "build-report"
+	}
+	start := fmt.Sprintf("<!-- concourse-ci:%s:start -->", name)
+	end := fmt.Sprintf("<!-- concourse-ci:%s:end -->", name)
+	section := start + "\n" + strings.TrimSpace(content) + "\n" + end
+
+	i := strings.Index(body, start)
+	j := strings.Index(body, end)
+	if i < 0 || j < i {
+		if strings.TrimSpace(body) == "" {
+			return section
+		}
+		return strings.TrimRight(body, "\r\n") + "\n\n" + section
+	}
+	return body[:i] + section + body[j+len(end):]
+}
+
+// waitForChecks polls the statuses and check runs of a commit until each of the
+// given contexts has reached a terminal state, or until the timeout passes. It
+// returns the last known state of each context, and an error unless all of them
+// succeeded.
+func waitForChecks(github models.Github, commitRef string, p *WaitForChecksParameters) (map[string]string, error) {
+	timeout, _ := parseDuration(p.Timeout, 30*time.Minute)
+	interval, _ := parseDuration(p.Interval, 30*time.Second)
+	deadline := time.Now().Add(timeout)
+
+	for {
+		checks, err := github.ListCommitChecks(commitRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list checks: %v", err)
+		}
+
+		states := make(map[string]string, len(p.Contexts))
+		var pending, failed []string
+		for _, name := range p.Contexts {
+			// Contexts that have not been reported yet are shown as "expected" by Github.
+			state := "expected"
+			for _, c := range checks {
+				if c.Name == name {
+					state = c.State
+					break
+				}
+			}
+			states[name] = state
+
+			switch {
+			case isPendingCheck(state):
+				pending = append(pending, name)
+			case state == "success", state == "neutral", state == "skipped":
+			default:
+				failed = append(failed, fmt.Sprintf("%s (%s)", name, state))
+			}
+		}
+
+		if len(pending) == 0 {
+			if len(failed) > 0 {
+				return states, fmt.Errorf("checks did not succeed: %s", strings.Join(failed, ", "))
+			}
+			return states, nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return states, fmt.Errorf("timed out waiting for checks: %s", strings.Join(pending, ", "))
+		}
+		time.Sleep(interval)
+	}
+}
+
+// expandMetadataMap returns a copy of m in which the metadata is expanded in
+// every value.
+func expandMetadataMap(m map[string]string, metadata models.Metadata) map[string]string {
+	if m == nil {
+		return nil
+	}
+	expanded := make(map[string]string, len(m))
+	for k, v := range m {
+		expanded[k] = models.SafeExpandMetadata(v, metadata)
+	}
+	return expanded
+}
+
+// newDispatchID returns a random ID for a workflow dispatch.
+func newDispatchID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate dispatch ID: %v", err)
+	}
+	return fmt.Sprintf("concourse-ci-%x", b), nil
+}
+
+// backportCommentMarker identifies the comment with the backport results, which
+// is updated by later puts instead of posting a new one.
+const backportCommentMarker = "<!-- concourse-ci:backport -->"
+
+// backport cherry-picks commits onto a new branch created from branch, and
+// opens a pull request for it. A backport that was (partly) done before, e.g.
+// by a previous attempt of the put, is completed instead. Returns the outcome
+// as a human readable string.
+func backport(github models.Github, git models.Git, pull *models.PullRequest, branch string, commits []string) string {
+	newBranch := fmt.Sprintf("backport-%d-to-%s", pull.Number, branch)
+	number, err := github.FindPullRequest(newBranch)
+	if err != nil {
+		return fmt.Sprintf("failed: %s", err)
+	}
+	if number != 0 {
+		return fmt.Sprintf("already opened #%d", number)
+	}
+
+	exists, err := github.BranchExists(newBranch)
+	if err != nil {
+		return fmt.Sprintf("failed: %s", err)
+	}
+	if !exists {
+		conflicts, err := git.CherryPick(pull.Repository.URL, branch, newBranch, commits)
+		if err != nil {
+			return fmt.Sprintf("failed: %s", err)
+		}
+		if len(conflicts) > 0 {
+			return fmt.Sprintf("conflicts in %s", strings.Join(conflicts, ", "))
+		}
+		if err := git.Push(pull.Repository.URL, newBranch, ""); err != nil {
+			return fmt.Sprintf("failed: %s", err)
+		}
+	}
+
+	title := fmt.Sprintf("[%s] %s", branch, pull.Title)
+	body := fmt.Sprintf("Backport of #%d to `%s`.", pull.Number, branch)
+	number, err = github.CreatePullRequest(branch, newBranch, title, body)
+	if err != nil {
+		return fmt.Sprintf("failed: %s", err)
+	}
+	return fmt.Sprintf("opened #%d", number)
+}
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
 		}
-		return "$" + v
-	})
+	}
+	return false
+}
+
+// isPendingCheck returns true if state is that of a status or check run that
+// has not completed yet.
+func isPendingCheck(state string) bool {
+	switch state {
+	case "expected", "pending", "queued", "in_progress", "waiting", "requested":
+		return true
+	}
+	return false
+}
+
+// parseDuration parses s, or returns def if s is empty.
+func parseDuration(s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+	return time.ParseDuration(s)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func isMergeMethod(method string) bool {
+	switch strings.ToLower(method) {
+	case "", "merge", "squash", "rebase":
+		return true
+	}
+	return false
 }
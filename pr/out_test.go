@@ -1,9 +1,14 @@
 package pr_test
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
 	"github.com/cloudfoundry-community/github-pr-instances-resource/models/fakes"
@@ -179,12 +184,225 @@ func TestPut(t *testing.T) {
 			},
 			pullRequest: test_helpers.CreateTestPR(1, "master", false, false, 0, []string{}, false, githubv4.PullRequestStateOpen),
 		},
+
+		{
+			description: "we can merge the pull request",
+			source: pr.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Number: 1,
+			},
+			version: pr.Version{
+				Ref: "commit1",
+			},
+			parameters: pr.PutParameters{
+				Merge: &pr.MergeParameters{
+					Method:        "squash",
+					CommitTitle:   "title",
+					CommitMessage: "message",
+				},
+			},
+			pullRequest: test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+
+		{
+			description: "we can delete the head branch after merging",
+			source: pr.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Number: 1,
+			},
+			version: pr.Version{
+				Ref: "commit1",
+			},
+			parameters: pr.PutParameters{
+				Merge: &pr.MergeParameters{
+					DeleteBranch: true,
+				},
+			},
+			pullRequest: test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+
+		{
+			description: "we do not delete the head branch of a fork after merging",
+			source: pr.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Number: 1,
+			},
+			version: pr.Version{
+				Ref: "commit1",
+			},
+			parameters: pr.PutParameters{
+				Merge: &pr.MergeParameters{
+					DeleteBranch: true,
+				},
+			},
+			pullRequest: test_helpers.CreateTestPR(1, "master", false, true, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+
+		{
+			description: "we can close the pull request",
+			source: pr.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Number: 1,
+			},
+			version: pr.Version{
+				Ref: "commit1",
+			},
+			parameters: pr.PutParameters{
+				State: "closed",
+			},
+			pullRequest: test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+
+		{
+			description: "we can reopen the pull request",
+			source: pr.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Number: 1,
+			},
+			version: pr.Version{
+				Ref: "commit1",
+			},
+			parameters: pr.PutParameters{
+				State: "open",
+			},
+			pullRequest: test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateClosed),
+		},
+
+		{
+			description: "we do not close a pull request that is already closed",
+			source: pr.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Number: 1,
+			},
+			version: pr.Version{
+				Ref: "commit1",
+			},
+			parameters: pr.PutParameters{
+				State: "closed",
+			},
+			pullRequest: test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateClosed),
+		},
+
+		{
+			description: "we can convert the pull request to a draft",
+			source: pr.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Number: 1,
+			},
+			version: pr.Version{
+				Ref: "commit1",
+			},
+			parameters: pr.PutParameters{
+				Draft: &[]bool{true}[0],
+			},
+			pullRequest: test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+
+		{
+			description: "we can mark a draft as ready for review",
+			source: pr.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Number: 1,
+			},
+			version: pr.Version{
+				Ref: "commit1",
+			},
+			parameters: pr.PutParameters{
+				Draft: &[]bool{false}[0],
+			},
+			pullRequest: test_helpers.CreateTestPR(1, "master", false, false, 0, nil, true, githubv4.PullRequestStateOpen),
+		},
+
+		{
+			description: "we can enable auto-merge",
+			source: pr.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Number: 1,
+			},
+			version: pr.Version{
+				Ref: "commit1",
+			},
+			parameters: pr.PutParameters{
+				AutoMerge: &pr.AutoMergeParameters{
+					Method:         "squash",
+					CommitHeadline: "headline",
+					CommitBody:     "body",
+				},
+			},
+			pullRequest: test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
+
+		{
+			description: "we can disable auto-merge",
+			source: pr.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Number: 1,
+			},
+			version: pr.Version{
+				Ref: "commit1",
+			},
+			parameters: pr.PutParameters{
+				DisableAutoMerge: true,
+			},
+			pullRequest: test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
 			github := new(fakes.FakeGithub)
 			github.GetPullRequestReturns(tc.pullRequest, nil)
+			github.EnablePullRequestAutoMergeReturns(&models.AutoMergeRequestObject{MergeMethod: githubv4.PullRequestMergeMethodSquash}, nil)
 
 			git := new(fakes.FakeGit)
 			git.RevParseReturns("sha", nil)
@@ -199,7 +417,7 @@ func TestPut(t *testing.T) {
 			require.NoError(t, err)
 
 			putInput := pr.PutRequest{Source: tc.source, Params: tc.parameters}
-			output, err := pr.Put(putInput, github, dir)
+			output, err := pr.Put(putInput, github, git, dir)
 
 			// Validate output
 			if assert.NoError(t, err) {
@@ -220,8 +438,9 @@ func TestPut(t *testing.T) {
 			}
 
 			if tc.parameters.Comment != "" {
-				if assert.Equal(t, 1, github.PostCommentCallCount()) {
-					pr, comment := github.PostCommentArgsForCall(0)
+				if assert.Equal(t, 1, github.UpsertCommentCallCount()) {
+					pr, marker, comment := github.UpsertCommentArgsForCall(0)
+					assert.True(t, strings.HasPrefix(marker, "<!-- concourse-ci:comment:"))
 					assert.Equal(t, tc.pullRequest.Number, pr)
 					assert.Equal(t, tc.parameters.Comment, comment)
 				}
@@ -233,6 +452,69 @@ func TestPut(t *testing.T) {
 					assert.Equal(t, tc.pullRequest.Number, pr)
 				}
 			}
+
+			closeCount, reopenCount := 0, 0
+			switch {
+			case tc.parameters.State == "closed" && tc.pullRequest.State == githubv4.PullRequestStateOpen:
+				closeCount = 1
+			case tc.parameters.State == "open" && tc.pullRequest.State == githubv4.PullRequestStateClosed:
+				reopenCount = 1
+			}
+			if assert.Equal(t, closeCount, github.ClosePullRequestCallCount()) && closeCount > 0 {
+				assert.Equal(t, tc.pullRequest.ID, github.ClosePullRequestArgsForCall(0))
+			}
+			if assert.Equal(t, reopenCount, github.ReopenPullRequestCallCount()) && reopenCount > 0 {
+				assert.Equal(t, tc.pullRequest.ID, github.ReopenPullRequestArgsForCall(0))
+			}
+
+			if tc.parameters.Draft != nil {
+				if *tc.parameters.Draft {
+					if assert.Equal(t, 1, github.ConvertPullRequestToDraftCallCount()) {
+						assert.Equal(t, tc.pullRequest.ID, github.ConvertPullRequestToDraftArgsForCall(0))
+					}
+				} else {
+					if assert.Equal(t, 1, github.MarkPullRequestReadyForReviewCallCount()) {
+						assert.Equal(t, tc.pullRequest.ID, github.MarkPullRequestReadyForReviewArgsForCall(0))
+					}
+				}
+			}
+
+			if tc.parameters.AutoMerge != nil {
+				if assert.Equal(t, 1, github.EnablePullRequestAutoMergeCallCount()) {
+					id, sha, method, headline, body := github.EnablePullRequestAutoMergeArgsForCall(0)
+					assert.Equal(t, tc.pullRequest.ID, id)
+					assert.Equal(t, tc.version.Ref, sha)
+					assert.Equal(t, tc.parameters.AutoMerge.Method, method)
+					assert.Equal(t, tc.parameters.AutoMerge.CommitHeadline, headline)
+					assert.Equal(t, tc.parameters.AutoMerge.CommitBody, body)
+				}
+				assert.Contains(t, output.Metadata, &models.MetadataField{Name: "auto_merge_method", Value: "squash"})
+			}
+
+			if tc.parameters.DisableAutoMerge {
+				if assert.Equal(t, 1, github.DisablePullRequestAutoMergeCallCount()) {
+					assert.Equal(t, tc.pullRequest.ID, github.DisablePullRequestAutoMergeArgsForCall(0))
+				}
+			}
+
+			if tc.parameters.Merge != nil {
+				if assert.Equal(t, 1, github.MergePullRequestCallCount()) {
+					pr, sha, method, title, message := github.MergePullRequestArgsForCall(0)
+					assert.Equal(t, tc.pullRequest.Number, pr)
+					assert.Equal(t, tc.version.Ref, sha)
+					assert.Equal(t, tc.parameters.Merge.Method, method)
+					assert.Equal(t, tc.parameters.Merge.CommitTitle, title)
+					assert.Equal(t, tc.parameters.Merge.CommitMessage, message)
+				}
+
+				if tc.parameters.Merge.DeleteBranch && !tc.pullRequest.IsCrossRepository {
+					if assert.Equal(t, 1, github.DeleteBranchCallCount()) {
+						assert.Equal(t, tc.pullRequest.HeadRefName, github.DeleteBranchArgsForCall(0))
+					}
+				} else {
+					assert.Equal(t, 0, github.DeleteBranchCallCount())
+				}
+			}
 		})
 	}
 }
@@ -246,13 +528,14 @@ func TestVariableSubstitution(t *testing.T) {
 	)
 
 	tests := []struct {
-		description       string
-		source            pr.Source
-		version           pr.Version
-		parameters        pr.PutParameters
-		expectedComment   string
-		expectedTargetURL string
-		pullRequest       *models.PullRequest
+		description         string
+		source              pr.Source
+		version             pr.Version
+		parameters          pr.PutParameters
+		expectedComment     string
+		expectedTargetURL   string
+		expectedCommitTitle string
+		pullRequest         *models.PullRequest
 	}{
 
 		{
@@ -315,6 +598,29 @@ func TestVariableSubstitution(t *testing.T) {
 			expectedComment: "$THIS_IS_NOT_SUBSTITUTED",
 			pullRequest:     test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
 		},
+
+		{
+			description: "we can substitute metadata and environment variables for the merge commit title",
+			source: pr.Source{
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				Number: 1,
+			},
+			version: pr.Version{
+				Ref: "commit1",
+			},
+			parameters: pr.PutParameters{
+				Merge: &pr.MergeParameters{
+					CommitTitle: fmt.Sprintf("$title (#$pr) by $%s", variableName),
+				},
+			},
+			expectedCommitTitle: fmt.Sprintf("pr1 title (#1) by %s", variableValue),
+			pullRequest:         test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+		},
 	}
 
 	for _, tc := range tests {
@@ -339,7 +645,7 @@ func TestVariableSubstitution(t *testing.T) {
 			os.Setenv(variableName, variableValue)
 
 			putInput := pr.PutRequest{Source: tc.source, Params: tc.parameters}
-			_, err = pr.Put(putInput, github, dir)
+			_, err = pr.Put(putInput, github, git, dir)
 
 			if tc.parameters.TargetURL != "" {
 				if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
@@ -349,12 +655,1453 @@ func TestVariableSubstitution(t *testing.T) {
 			}
 
 			if tc.parameters.Comment != "" {
-				if assert.Equal(t, 1, github.PostCommentCallCount()) {
-					_, comment := github.PostCommentArgsForCall(0)
+				if assert.Equal(t, 1, github.UpsertCommentCallCount()) {
+					_, marker, comment := github.UpsertCommentArgsForCall(0)
+					assert.True(t, strings.HasPrefix(marker, "<!-- concourse-ci:comment:"))
 					assert.Equal(t, tc.expectedComment, comment)
 				}
 			}
 
+			if tc.parameters.Merge != nil {
+				if assert.Equal(t, 1, github.MergePullRequestCallCount()) {
+					_, _, _, title, _ := github.MergePullRequestArgsForCall(0)
+					assert.Equal(t, tc.expectedCommitTitle, title)
+				}
+			}
+
 		})
 	}
 }
+
+// putSource is the source used by the tests for individual put actions.
+var putSource = pr.Source{
+	GithubConfig: models.GithubConfig{
+		Repository: "itsdalmo/test-repository",
+	},
+	CommonConfig: models.CommonConfig{
+		AccessToken: "oauthtoken",
+	},
+	Number: 1,
+}
+
+// getForPut runs get for the version of pull, so that put can be run against
+// its output. The directory is removed when the test ends.
+func getForPut(t *testing.T, pull *models.PullRequest, version pr.Version) (*fakes.FakeGithub, *fakes.FakeGit, string) {
+	github := new(fakes.FakeGithub)
+	github.GetPullRequestReturns(pull, nil)
+
+	git := new(fakes.FakeGit)
+	git.RevParseReturns("sha", nil)
+
+	dir := test_helpers.CreateTestDirectory(t)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	getInput := pr.GetRequest{Source: putSource, Version: version, Params: pr.GetParameters{}}
+	_, err := pr.Get(getInput, github, git, dir)
+	require.NoError(t, err)
+	return github, git, dir
+}
+
+func TestPutStatuses(t *testing.T) {
+
+	tests := []struct {
+		description  string
+		parameters   pr.PutParameters
+		statusesFile string
+		expected     []pr.StatusParameters
+		expectErr    bool
+	}{
+		{
+			description: "we can set multiple statuses",
+			parameters: pr.PutParameters{
+				Statuses: []pr.StatusParameters{
+					{Context: "lint", State: "success"},
+					{Context: "unit", State: "failure", Description: "2 tests failed", TargetURL: "https://targeturl.com/concourse"},
+				},
+			},
+			expected: []pr.StatusParameters{
+				{Context: "lint", State: "success"},
+				{Context: "unit", State: "failure", Description: "2 tests failed", TargetURL: "https://targeturl.com/concourse"},
+			},
+		},
+
+		{
+			description: "we can read statuses from a file",
+			parameters: pr.PutParameters{
+				Statuses: []pr.StatusParameters{
+					{Context: "lint", State: "success"},
+				},
+				StatusesFile: "statuses.json",
+			},
+			statusesFile: `[{"context": "unit", "state": "pending"}, {"context": "integration", "state": "error"}]`,
+			expected: []pr.StatusParameters{
+				{Context: "lint", State: "success"},
+				{Context: "unit", State: "pending"},
+				{Context: "integration", State: "error"},
+			},
+		},
+
+		{
+			description: "no statuses are set if one of them is invalid",
+			parameters: pr.PutParameters{
+				Statuses: []pr.StatusParameters{
+					{Context: "lint", State: "success"},
+				},
+				StatusesFile: "statuses.json",
+			},
+			statusesFile: `[{"context": "unit", "state": "skipped"}]`,
+			expectErr:    true,
+		},
+
+		{
+			description: "statuses must have a context",
+			parameters: pr.PutParameters{
+				Statuses: []pr.StatusParameters{
+					{State: "success"},
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			version := pr.Version{Ref: "commit1"}
+			github, git, dir := getForPut(t, test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), version)
+
+			if tc.statusesFile != "" {
+				err := ioutil.WriteFile(filepath.Join(dir, tc.parameters.StatusesFile), []byte(tc.statusesFile), 0644)
+				require.NoError(t, err)
+			}
+
+			putInput := pr.PutRequest{Source: putSource, Params: tc.parameters}
+			_, err := pr.Put(putInput, github, git, dir)
+
+			if tc.expectErr {
+				assert.Error(t, err)
+				assert.Equal(t, 0, github.UpdateCommitStatusCallCount())
+				return
+			}
+
+			if assert.NoError(t, err) && assert.Equal(t, len(tc.expected), github.UpdateCommitStatusCallCount()) {
+				for i, expected := range tc.expected {
+					commit, _, context, state, targetURL, description := github.UpdateCommitStatusArgsForCall(i)
+					assert.Equal(t, version.Ref, commit)
+					assert.Equal(t, expected.Context, context)
+					assert.Equal(t, expected.State, state)
+					assert.Equal(t, expected.TargetURL, targetURL)
+					assert.Equal(t, expected.Description, description)
+				}
+			}
+		})
+	}
+}
+
+func TestPutBodySection(t *testing.T) {
+
+	tests := []struct {
+		description string
+		parameters  pr.PutParameters
+		contentFile string
+		body        string
+		expected    string
+	}{
+		{
+			description: "we can add a section to an empty description",
+			parameters: pr.PutParameters{
+				BodySection: &pr.BodySectionParameters{Content: "All tests passed"},
+			},
+			body:     "",
+			expected: "<!-- concourse-ci:build-report:start -->\nAll tests passed\n<!-- concourse-ci:build-report:end -->",
+		},
+
+		{
+			description: "we append the section to the existing description",
+			parameters: pr.PutParameters{
+				BodySection: &pr.BodySectionParameters{Name: "report", Content: "All tests passed"},
+			},
+			body:     "Fixes a bug.\r\n",
+			expected: "Fixes a bug.\n\n<!-- concourse-ci:report:start -->\nAll tests passed\n<!-- concourse-ci:report:end -->",
+		},
+
+		{
+			description: "we replace an existing section and preserve the rest of the description",
+			parameters: pr.PutParameters{
+				BodySection: &pr.BodySectionParameters{Content: "Tests for $title failed"},
+			},
+			body:     "Before\n<!-- concourse-ci:build-report:start -->\nAll tests passed\n<!-- concourse-ci:build-report:end -->\nAfter",
+			expected: "Before\n<!-- concourse-ci:build-report:start -->\nTests for pr1 title failed\n<!-- concourse-ci:build-report:end -->\nAfter",
+		},
+
+		{
+			description: "we can read the content of the section from a file",
+			parameters: pr.PutParameters{
+				BodySection: &pr.BodySectionParameters{ContentFile: "report.md"},
+			},
+			contentFile: "| test | result |\n",
+			body:        "Before",
+			expected:    "Before\n\n<!-- concourse-ci:build-report:start -->\n| test | result |\n<!-- concourse-ci:build-report:end -->",
+		},
+
+		{
+			description: "we do not update the description when the section is unchanged",
+			parameters: pr.PutParameters{
+				BodySection: &pr.BodySectionParameters{Content: "All tests passed"},
+			},
+			body: "<!-- concourse-ci:build-report:start -->\nAll tests passed\n<!-- concourse-ci:build-report:end -->",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			version := pr.Version{Ref: "commit1"}
+			github, git, dir := getForPut(t, test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), version)
+			github.GetPullRequestBodyReturns(tc.body, nil)
+
+			if tc.contentFile != "" {
+				err := ioutil.WriteFile(filepath.Join(dir, tc.parameters.BodySection.ContentFile), []byte(tc.contentFile), 0644)
+				require.NoError(t, err)
+			}
+
+			putInput := pr.PutRequest{Source: putSource, Params: tc.parameters}
+			_, err := pr.Put(putInput, github, git, dir)
+			require.NoError(t, err)
+
+			if tc.expected == "" {
+				assert.Equal(t, 0, github.UpdatePullRequestBodyCallCount())
+				return
+			}
+			if assert.Equal(t, 1, github.UpdatePullRequestBodyCallCount()) {
+				number, body := github.UpdatePullRequestBodyArgsForCall(0)
+				assert.Equal(t, putSource.Number, number)
+				assert.Equal(t, tc.expected, body)
+			}
+		})
+	}
+}
+
+func TestPutDeployment(t *testing.T) {
+	version := pr.Version{Ref: "commit1"}
+
+	// Deployments by environment, newest first as returned by GitHub.
+	deployments := map[string][]int64{}
+	github, git, dir := getForPut(t, test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), version)
+	github.CreateDeploymentStub = func(ref, environment, _ string) (int64, error) {
+		id := int64(41 + github.CreateDeploymentCallCount())
+		deployments[environment] = append([]int64{id}, deployments[environment]...)
+		return id, nil
+	}
+	github.ListDeploymentsStub = func(ref, environment string) ([]int64, error) {
+		return deployments[environment], nil
+	}
+
+	t.Run("we create a deployment for the version", func(t *testing.T) {
+		params := pr.PutParameters{Deployment: &pr.DeploymentParameters{State: "in_progress"}}
+		output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+		require.NoError(t, err)
+
+		if assert.Equal(t, 1, github.ListDeploymentsCallCount()) {
+			ref, environment := github.ListDeploymentsArgsForCall(0)
+			assert.Equal(t, version.Ref, ref)
+			assert.Equal(t, "pr-1", environment)
+		}
+		if assert.Equal(t, 1, github.CreateDeploymentCallCount()) {
+			ref, environment, _ := github.CreateDeploymentArgsForCall(0)
+			assert.Equal(t, version.Ref, ref)
+			assert.Equal(t, "pr-1", environment)
+		}
+		if assert.Equal(t, 1, github.CreateDeploymentStatusCallCount()) {
+			id, state, _, _, _ := github.CreateDeploymentStatusArgsForCall(0)
+			assert.Equal(t, int64(42), id)
+			assert.Equal(t, "in_progress", state)
+		}
+		assert.Contains(t, output.Metadata, &models.MetadataField{Name: "deployment_id", Value: "42"})
+	})
+
+	t.Run("we create a separate deployment for another environment", func(t *testing.T) {
+		params := pr.PutParameters{Deployment: &pr.DeploymentParameters{Environment: "staging", State: "in_progress"}}
+		output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+		require.NoError(t, err)
+
+		if assert.Equal(t, 2, github.CreateDeploymentCallCount()) {
+			_, environment, _ := github.CreateDeploymentArgsForCall(1)
+			assert.Equal(t, "staging", environment)
+		}
+		assert.Contains(t, output.Metadata, &models.MetadataField{Name: "deployment_id", Value: "43"})
+	})
+
+	t.Run("we reuse the deployment of the environment in subsequent puts", func(t *testing.T) {
+		for _, tc := range []struct {
+			environment string
+			id          int64
+		}{{"", 42}, {"staging", 43}} {
+			params := pr.PutParameters{Deployment: &pr.DeploymentParameters{Environment: tc.environment, State: "success", EnvironmentURL: "https://pr-$pr.example.com"}}
+			_, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+			require.NoError(t, err)
+
+			calls := github.CreateDeploymentStatusCallCount()
+			id, state, environmentURL, _, _ := github.CreateDeploymentStatusArgsForCall(calls - 1)
+			assert.Equal(t, tc.id, id)
+			assert.Equal(t, "success", state)
+			assert.Equal(t, "https://pr-1.example.com", environmentURL)
+		}
+		assert.Equal(t, 2, github.CreateDeploymentCallCount())
+	})
+
+	t.Run("we can deactivate all deployments to the environment", func(t *testing.T) {
+		deployments["preview-1"] = []int64{41, 40}
+		params := pr.PutParameters{Deployment: &pr.DeploymentParameters{Environment: "preview-$pr", State: "inactive"}}
+		_, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+		require.NoError(t, err)
+
+		calls := github.ListDeploymentsCallCount()
+		ref, environment := github.ListDeploymentsArgsForCall(calls - 1)
+		assert.Equal(t, "", ref)
+		assert.Equal(t, "preview-1", environment)
+		if assert.Equal(t, 6, github.CreateDeploymentStatusCallCount()) {
+			for i, expected := range []int64{41, 40} {
+				id, state, _, _, _ := github.CreateDeploymentStatusArgsForCall(4 + i)
+				assert.Equal(t, expected, id)
+				assert.Equal(t, "inactive", state)
+			}
+		}
+	})
+}
+
+func TestPutSuperseded(t *testing.T) {
+
+	tests := []struct {
+		description     string
+		parameters      pr.PutParameters
+		head            string
+		expectedComment string
+		expectDelete    bool
+	}{
+		{
+			description: "we comment as usual when the version is the head of the pull request",
+			parameters: pr.PutParameters{
+				Status:                 "success",
+				Comment:                "tests passed",
+				DeletePreviousComments: true,
+				Superseded:             "skip",
+			},
+			head:            "commit1234567890",
+			expectedComment: "tests passed",
+			expectDelete:    true,
+		},
+
+		{
+			description: "we skip comments when the version has been superseded",
+			parameters: pr.PutParameters{
+				Status:                 "success",
+				Comment:                "tests passed",
+				DeletePreviousComments: true,
+				Superseded:             "skip",
+			},
+			head: "commit2",
+		},
+
+		{
+			description: "we annotate comments when the version has been superseded",
+			parameters: pr.PutParameters{
+				Status:     "success",
+				Comment:    "tests passed",
+				Superseded: "annotate",
+			},
+			head:            "commit2",
+			expectedComment: "> Results for older commit commit1\n\ntests passed",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			version := pr.Version{Ref: "commit1234567890"}
+			github, git, dir := getForPut(t, test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), version)
+			github.GetPullRequestHeadSHAReturns(tc.head, nil)
+
+			putInput := pr.PutRequest{Source: putSource, Params: tc.parameters}
+			output, err := pr.Put(putInput, github, git, dir)
+			require.NoError(t, err)
+
+			superseded := tc.head != version.Ref
+			assert.Contains(t, output.Metadata, &models.MetadataField{Name: "superseded", Value: fmt.Sprint(superseded)})
+
+			// The status is always set on the commit of the version.
+			if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
+				commit, _, _, _, _, _ := github.UpdateCommitStatusArgsForCall(0)
+				assert.Equal(t, version.Ref, commit)
+			}
+
+			if tc.expectedComment == "" {
+				assert.Equal(t, 0, github.UpsertCommentCallCount())
+			} else if assert.Equal(t, 1, github.UpsertCommentCallCount()) {
+				_, marker, comment := github.UpsertCommentArgsForCall(0)
+				assert.True(t, strings.HasPrefix(marker, "<!-- concourse-ci:comment:"))
+				assert.Equal(t, tc.expectedComment, comment)
+			}
+
+			if tc.expectDelete {
+				assert.Equal(t, 1, github.DeletePreviousCommentsCallCount())
+			} else {
+				assert.Equal(t, 0, github.DeletePreviousCommentsCallCount())
+			}
+		})
+	}
+}
+
+func TestPutWaitForChecks(t *testing.T) {
+
+	tests := []struct {
+		description      string
+		parameters       pr.PutParameters
+		checks           [][]models.CommitCheck
+		expectedErr      string
+		expectedMetadata []*models.MetadataField
+	}{
+		{
+			description: "we wait until all contexts have succeeded",
+			parameters: pr.PutParameters{
+				WaitForChecks: &pr.WaitForChecksParameters{Contexts: []string{"security/scan", "other-ci"}, Interval: "1ms"},
+			},
+			checks: [][]models.CommitCheck{
+				{{Name: "security/scan", State: "pending"}},
+				{{Name: "security/scan", State: "success"}, {Name: "other-ci", State: "in_progress"}},
+				{{Name: "security/scan", State: "success"}, {Name: "other-ci", State: "neutral"}, {Name: "unrelated", State: "failure"}},
+			},
+			expectedMetadata: []*models.MetadataField{
+				{Name: "check_security/scan", Value: "success"},
+				{Name: "check_other-ci", Value: "neutral"},
+			},
+		},
+
+		{
+			description: "we fail when one of the contexts did not succeed",
+			parameters: pr.PutParameters{
+				WaitForChecks: &pr.WaitForChecksParameters{Contexts: []string{"security/scan", "other-ci"}, Interval: "1ms"},
+			},
+			checks: [][]models.CommitCheck{
+				{{Name: "security/scan", State: "failure"}, {Name: "other-ci", State: "success"}},
+			},
+			expectedErr: "checks did not succeed: security/scan (failure)",
+		},
+
+		{
+			description: "we record the states of failed contexts with continue_on_error",
+			parameters: pr.PutParameters{
+				ContinueOnError: true,
+				WaitForChecks:   &pr.WaitForChecksParameters{Contexts: []string{"security/scan", "other-ci"}, Interval: "1ms"},
+			},
+			checks: [][]models.CommitCheck{
+				{{Name: "security/scan", State: "failure"}, {Name: "other-ci", State: "success"}},
+			},
+			expectedMetadata: []*models.MetadataField{
+				{Name: "check_security/scan", Value: "failure"},
+				{Name: "check_other-ci", Value: "success"},
+				{Name: "action_wait_for_checks", Value: "failed: checks did not succeed: security/scan (failure)"},
+			},
+		},
+
+		{
+			description: "we fail when the contexts do not complete in time",
+			parameters: pr.PutParameters{
+				WaitForChecks: &pr.WaitForChecksParameters{Contexts: []string{"security/scan"}, Timeout: "5ms", Interval: "1ms"},
+			},
+			checks: [][]models.CommitCheck{
+				{},
+			},
+			expectedErr: "timed out waiting for checks: security/scan",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			version := pr.Version{Ref: "commit1"}
+			github, git, dir := getForPut(t, test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), version)
+			for i, checks := range tc.checks {
+				github.ListCommitChecksReturnsOnCall(i, checks, nil)
+			}
+			github.ListCommitChecksReturns(tc.checks[len(tc.checks)-1], nil)
+
+			putInput := pr.PutRequest{Source: putSource, Params: tc.parameters}
+			output, err := pr.Put(putInput, github, git, dir)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, len(tc.checks), github.ListCommitChecksCallCount())
+				assert.Equal(t, version.Ref, github.ListCommitChecksArgsForCall(0))
+				for _, m := range tc.expectedMetadata {
+					assert.Contains(t, output.Metadata, m)
+				}
+			}
+		})
+	}
+}
+
+func TestPutPush(t *testing.T) {
+	fork := test_helpers.CreateTestPR(1, "master", false, true, 0, nil, false, githubv4.PullRequestStateOpen)
+	fork.HeadRepository.URL = "https://github.com/contributor/test-repository"
+
+	editableFork := test_helpers.CreateTestPR(1, "master", false, true, 0, nil, false, githubv4.PullRequestStateOpen)
+	editableFork.HeadRepository.URL = "https://github.com/contributor/test-repository"
+	editableFork.MaintainerCanModify = true
+
+	tests := []struct {
+		description      string
+		pullRequest      *models.PullRequest
+		headSHA          string
+		committed        bool
+		expectedURI      string
+		expectedErr      string
+		expectedMetadata *models.MetadataField
+	}{
+		{
+			description:      "we commit and push changes to the head branch",
+			pullRequest:      test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			headSHA:          "commit1",
+			committed:        true,
+			expectedURI:      "repo1 url",
+			expectedMetadata: &models.MetadataField{Name: "pushed_sha", Value: "sha"},
+		},
+
+		{
+			description:      "we do not push when there is nothing to commit",
+			pullRequest:      test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			headSHA:          "commit1",
+			committed:        false,
+			expectedMetadata: &models.MetadataField{Name: "pushed_sha", Value: "none (nothing to commit)"},
+		},
+
+		{
+			description: "we refuse to push when the head has moved",
+			pullRequest: test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			headSHA:     "commit2",
+			committed:   true,
+			expectedErr: "failed to push: head of pull request has moved from commit1 to commit2",
+		},
+
+		{
+			description: "we refuse to push to a fork that maintainers can not modify",
+			pullRequest: fork,
+			headSHA:     "commit1",
+			committed:   true,
+			expectedErr: "failed to push: the author of the pull request does not allow maintainers to modify the head branch",
+		},
+
+		{
+			description:      "we push to the head repository of a fork that maintainers can modify",
+			pullRequest:      editableFork,
+			headSHA:          "commit1",
+			committed:        true,
+			expectedURI:      "https://github.com/contributor/test-repository",
+			expectedMetadata: &models.MetadataField{Name: "pushed_sha", Value: "sha"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			version := pr.Version{Ref: "commit1"}
+			github, git, dir := getForPut(t, tc.pullRequest, version)
+			github.GetPullRequestHeadSHAReturns(tc.headSHA, nil)
+
+			git.CommitReturns(tc.committed, nil)
+
+			params := pr.PutParameters{Push: &pr.PushParameters{Repository: "formatted", Message: "Format $title"}}
+			output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Equal(t, 0, git.PushCallCount())
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, output.Metadata, tc.expectedMetadata)
+
+			if assert.Equal(t, 1, git.CommitCallCount()) {
+				message, name, email := git.CommitArgsForCall(0)
+				assert.Equal(t, "Format "+tc.pullRequest.Title+"\n\n"+models.PushedCommitTrailer, message)
+				assert.Equal(t, "concourse-ci", name)
+				assert.Equal(t, "concourse@local", email)
+			}
+
+			if !tc.committed {
+				assert.Equal(t, 0, git.PushCallCount())
+				return
+			}
+			if assert.Equal(t, 1, git.PushCallCount()) {
+				uri, branch, expectedSHA := git.PushArgsForCall(0)
+				assert.Equal(t, tc.expectedURI, uri)
+				assert.Equal(t, tc.pullRequest.HeadRefName, branch)
+				assert.Equal(t, version.Ref, expectedSHA)
+			}
+		})
+	}
+}
+
+func TestPutBackport(t *testing.T) {
+	tests := []struct {
+		description      string
+		state            githubv4.PullRequestState
+		labels           []string
+		parameters       pr.BackportParameters
+		conflicts        []string
+		existingPRs      map[string]int
+		existingBranches []string
+		expectedBranches []string
+		expectedOpened   []string
+		expectedErr      string
+		expectedMetadata []*models.MetadataField
+		expectedComment  string
+	}{
+		{
+			description:      "we backport to the branches from labels and parameters",
+			state:            githubv4.PullRequestStateMerged,
+			labels:           []string{"bug", "backport/release-1.x"},
+			parameters:       pr.BackportParameters{Branches: []string{"release-2.x"}},
+			expectedBranches: []string{"release-2.x", "release-1.x"},
+			expectedOpened:   []string{"release-2.x", "release-1.x"},
+			expectedMetadata: []*models.MetadataField{
+				{Name: "backport_release-2.x", Value: "opened #10"},
+				{Name: "backport_release-1.x", Value: "opened #10"},
+			},
+			expectedComment: "Backport results:\n\n- `release-2.x`: opened #10\n- `release-1.x`: opened #10",
+		},
+
+		{
+			description:      "we report conflicting files instead of opening a pull request",
+			state:            githubv4.PullRequestStateMerged,
+			parameters:       pr.BackportParameters{Branches: []string{"release-1.x"}},
+			conflicts:        []string{"main.go", "README.md"},
+			expectedBranches: []string{"release-1.x"},
+			expectedMetadata: []*models.MetadataField{
+				{Name: "backport_release-1.x", Value: "conflicts in main.go, README.md"},
+			},
+			expectedComment: "Backport results:\n\n- `release-1.x`: conflicts in main.go, README.md",
+		},
+
+		{
+			description:      "we complete backports that were started before",
+			state:            githubv4.PullRequestStateMerged,
+			parameters:       pr.BackportParameters{Branches: []string{"release-1.x", "release-2.x", "release-3.x"}},
+			existingPRs:      map[string]int{"backport-1-to-release-1.x": 7},
+			existingBranches: []string{"backport-1-to-release-2.x"},
+			expectedBranches: []string{"release-3.x"},
+			expectedOpened:   []string{"release-2.x", "release-3.x"},
+			expectedMetadata: []*models.MetadataField{
+				{Name: "backport_release-1.x", Value: "already opened #7"},
+				{Name: "backport_release-2.x", Value: "opened #10"},
+				{Name: "backport_release-3.x", Value: "opened #10"},
+			},
+			expectedComment: "Backport results:\n\n- `release-1.x`: already opened #7\n- `release-2.x`: opened #10\n- `release-3.x`: opened #10",
+		},
+
+		{
+			description: "we do nothing without target branches",
+			state:       githubv4.PullRequestStateMerged,
+			labels:      []string{"bug"},
+		},
+
+		{
+			description: "we refuse to backport a pull request that is not merged",
+			state:       githubv4.PullRequestStateOpen,
+			labels:      []string{"backport/release-1.x"},
+			expectedErr: "failed to backport: pull request is not merged",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			version := pr.Version{Ref: "commit1"}
+			pull := test_helpers.CreateTestPR(1, "master", false, false, 0, tc.labels, false, tc.state)
+
+			github, git, dir := getForPut(t, pull, version)
+			github.ListPullRequestCommitsReturns([]models.CommitObject{{OID: "a"}, {OID: "b"}}, nil)
+			github.CreatePullRequestReturns(10, nil)
+			github.FindPullRequestStub = func(head string) (int, error) {
+				return tc.existingPRs[head], nil
+			}
+			github.BranchExistsStub = func(branch string) (bool, error) {
+				for _, b := range tc.existingBranches {
+					if b == branch {
+						return true, nil
+					}
+				}
+				return false, nil
+			}
+
+			git.CherryPickReturns(tc.conflicts, nil)
+
+			params := pr.PutParameters{Backport: &tc.parameters}
+			output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Equal(t, 0, git.CherryPickCallCount())
+				return
+			}
+			require.NoError(t, err)
+			for _, m := range tc.expectedMetadata {
+				assert.Contains(t, output.Metadata, m)
+			}
+
+			if assert.Equal(t, len(tc.expectedBranches), git.CherryPickCallCount()) {
+				for i, branch := range tc.expectedBranches {
+					uri, base, newBranch, commits := git.CherryPickArgsForCall(i)
+					assert.Equal(t, pull.Repository.URL, uri)
+					assert.Equal(t, branch, base)
+					assert.Equal(t, "backport-1-to-"+branch, newBranch)
+					assert.Equal(t, []string{"a", "b"}, commits)
+				}
+			}
+
+			pushed := tc.expectedBranches
+			if len(tc.conflicts) > 0 {
+				pushed = nil
+			}
+			if assert.Equal(t, len(pushed), git.PushCallCount()) {
+				for i, branch := range pushed {
+					_, newBranch, expectedSHA := git.PushArgsForCall(i)
+					assert.Equal(t, "backport-1-to-"+branch, newBranch)
+					assert.Equal(t, "", expectedSHA)
+				}
+			}
+			if assert.Equal(t, len(tc.expectedOpened), github.CreatePullRequestCallCount()) {
+				for i, branch := range tc.expectedOpened {
+					base, head, title, _ := github.CreatePullRequestArgsForCall(i)
+					assert.Equal(t, branch, base)
+					assert.Equal(t, "backport-1-to-"+branch, head)
+					assert.Equal(t, "["+branch+"] "+pull.Title, title)
+				}
+			}
+
+			if tc.expectedComment == "" {
+				assert.Equal(t, 0, github.UpsertCommentCallCount())
+			} else if assert.Equal(t, 1, github.UpsertCommentCallCount()) {
+				number, marker, comment := github.UpsertCommentArgsForCall(0)
+				assert.Equal(t, 1, number)
+				assert.Equal(t, "<!-- concourse-ci:backport -->", marker)
+				assert.Equal(t, tc.expectedComment, comment)
+			}
+		})
+	}
+}
+
+func TestPutUpdateBranch(t *testing.T) {
+	tests := []struct {
+		description      string
+		method           string
+		behind           int
+		updateErr        error
+		expectUpdate     bool
+		expectedErr      string
+		expectedMetadata []*models.MetadataField
+	}{
+		{
+			description: "we do nothing when the branch is up to date",
+			behind:      0,
+			expectedMetadata: []*models.MetadataField{
+				{Name: "update_branch", Value: "up-to-date"},
+			},
+		},
+
+		{
+			description:  "we update the branch when it is behind",
+			method:       "rebase",
+			behind:       2,
+			expectUpdate: true,
+			expectedMetadata: []*models.MetadataField{
+				{Name: "update_branch", Value: "updated"},
+				{Name: "update_branch_sha", Value: "updated-sha"},
+			},
+		},
+
+		{
+			description:  "we report conflicts without failing",
+			behind:       2,
+			updateErr:    models.ErrMergeConflict,
+			expectUpdate: true,
+			expectedMetadata: []*models.MetadataField{
+				{Name: "update_branch", Value: "conflict"},
+			},
+		},
+
+		{
+			description:  "we fail on other errors",
+			behind:       2,
+			updateErr:    errors.New("expected head oid did not match"),
+			expectUpdate: true,
+			expectedErr:  "failed to update branch: expected head oid did not match",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			version := pr.Version{Ref: "commit1"}
+			pull := test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+
+			github, git, dir := getForPut(t, pull, version)
+			github.CountCommitsBehindReturns(tc.behind, nil)
+			github.UpdatePullRequestBranchReturns("updated-sha", tc.updateErr)
+
+			params := pr.PutParameters{UpdateBranch: &pr.UpdateBranchParameters{Method: tc.method}}
+			output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+
+			if assert.Equal(t, 1, github.CountCommitsBehindCallCount()) {
+				base, head := github.CountCommitsBehindArgsForCall(0)
+				assert.Equal(t, "master", base)
+				assert.Equal(t, version.Ref, head)
+			}
+			if !tc.expectUpdate {
+				assert.Equal(t, 0, github.UpdatePullRequestBranchCallCount())
+			} else if assert.Equal(t, 1, github.UpdatePullRequestBranchCallCount()) {
+				id, headSHA, method := github.UpdatePullRequestBranchArgsForCall(0)
+				assert.Equal(t, pull.ID, id)
+				assert.Equal(t, version.Ref, headSHA)
+				assert.Equal(t, tc.method, method)
+			}
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				for _, m := range tc.expectedMetadata {
+					assert.Contains(t, output.Metadata, m)
+				}
+			}
+		})
+	}
+}
+
+func TestPutDispatch(t *testing.T) {
+	version := pr.Version{Ref: "commit1"}
+	github, git, dir := getForPut(t, test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), version)
+
+	t.Run("we dispatch a workflow on the base branch with rendered inputs", func(t *testing.T) {
+		github.DispatchWorkflowReturns(&models.WorkflowRun{ID: 42, URL: "https://github.com/itsdalmo/test-repository/actions/runs/42"}, nil)
+
+		params := pr.PutParameters{WorkflowDispatch: &pr.WorkflowDispatchParameters{
+			Workflow: "ci.yml",
+			Inputs:   map[string]string{"pr": "$pr", "sha": "$head_sha"},
+			IDInput:  "dispatch_id",
+		}}
+		output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+		require.NoError(t, err)
+
+		if assert.Equal(t, 1, github.DispatchWorkflowCallCount()) {
+			workflow, ref, inputs, dispatchID := github.DispatchWorkflowArgsForCall(0)
+			assert.Equal(t, "ci.yml", workflow)
+			assert.Equal(t, "master", ref)
+			assert.NotEmpty(t, dispatchID)
+			assert.Equal(t, map[string]string{"pr": "1", "sha": "oid1", "dispatch_id": dispatchID}, inputs)
+		}
+		assert.Contains(t, output.Metadata, &models.MetadataField{Name: "workflow_run_id", Value: "42"})
+		assert.Contains(t, output.Metadata, &models.MetadataField{Name: "workflow_run_url", Value: "https://github.com/itsdalmo/test-repository/actions/runs/42"})
+	})
+
+	t.Run("we omit the run without an id_input", func(t *testing.T) {
+		github.DispatchWorkflowReturns(nil, nil)
+
+		params := pr.PutParameters{WorkflowDispatch: &pr.WorkflowDispatchParameters{Workflow: "ci.yml", Ref: "release-1.x"}}
+		output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+		require.NoError(t, err)
+
+		_, ref, inputs, dispatchID := github.DispatchWorkflowArgsForCall(1)
+		assert.Equal(t, "release-1.x", ref)
+		assert.Nil(t, inputs)
+		assert.Empty(t, dispatchID)
+		_, ok := output.Metadata.Get("workflow_run_id")
+		assert.False(t, ok)
+	})
+
+	t.Run("we send a repository_dispatch event with a rendered payload", func(t *testing.T) {
+		params := pr.PutParameters{RepositoryDispatch: &pr.RepositoryDispatchParameters{
+			EventType:     "pr-build",
+			ClientPayload: map[string]string{"pr": "$pr", "branch": "$head_name"},
+		}}
+		_, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+		require.NoError(t, err)
+
+		if assert.Equal(t, 1, github.DispatchRepositoryEventCallCount()) {
+			eventType, payload := github.DispatchRepositoryEventArgsForCall(0)
+			assert.Equal(t, "pr-build", eventType)
+			assert.Equal(t, map[string]string{"pr": "1", "branch": "pr1"}, payload)
+		}
+	})
+
+	t.Run("we require a workflow", func(t *testing.T) {
+		params := pr.PutParameters{WorkflowDispatch: &pr.WorkflowDispatchParameters{}}
+		_, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+		assert.EqualError(t, err, "invalid parameters: workflow_dispatch.workflow must be set")
+	})
+}
+
+func TestPutSemanticTitle(t *testing.T) {
+	tests := []struct {
+		description         string
+		title               string
+		parameters          pr.SemanticTitleParameters
+		expectedState       string
+		expectedDescription string
+	}{
+		{
+			description:         "a conventional title succeeds",
+			title:               "feat(api)!: add pagination",
+			expectedState:       "success",
+			expectedDescription: "Title follows Conventional Commits",
+		},
+
+		{
+			description:         "a title without type fails",
+			title:               "Add pagination",
+			expectedState:       "failure",
+			expectedDescription: "Title must look like 'type(scope): subject'",
+		},
+
+		{
+			description:         "an unknown type fails",
+			title:               "feature: add pagination",
+			parameters:          pr.SemanticTitleParameters{Types: []string{"feat", "fix"}},
+			expectedState:       "failure",
+			expectedDescription: "Unknown type 'feature', use one of: feat, fix",
+		},
+
+		{
+			description:         "an unknown scope fails",
+			title:               "fix(cli): handle empty input",
+			parameters:          pr.SemanticTitleParameters{Scopes: []string{"api", "web"}},
+			expectedState:       "failure",
+			expectedDescription: "Unknown scope 'cli', use one of: api, web",
+		},
+
+		{
+			description:         "a missing scope fails when required",
+			title:               "fix: handle empty input",
+			parameters:          pr.SemanticTitleParameters{RequireScope: true},
+			expectedState:       "failure",
+			expectedDescription: "Title must have a scope, e.g. 'type(scope): subject'",
+		},
+
+		{
+			description:         "a subject that does not match the pattern fails",
+			title:               "fix: Handle empty input",
+			parameters:          pr.SemanticTitleParameters{Pattern: "^[a-z]"},
+			expectedState:       "failure",
+			expectedDescription: "Subject must match ^[a-z]",
+		},
+
+		{
+			description:         "a long description is truncated between characters",
+			title:               "fix(clis): handle empty input",
+			parameters:          pr.SemanticTitleParameters{Scopes: []string{strings.Repeat("é", 150)}},
+			expectedState:       "failure",
+			expectedDescription: "Unknown scope 'clis', use one of: " + strings.Repeat("é", 103) + "...",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			version := pr.Version{Ref: "commit1"}
+			pull := test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+			pull.Title = tc.title
+
+			github, git, dir := getForPut(t, pull, version)
+
+			params := pr.PutParameters{SemanticTitle: &tc.parameters}
+			output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+			require.NoError(t, err)
+			assert.Contains(t, output.Metadata, &models.MetadataField{Name: "semantic_title", Value: tc.expectedState})
+
+			if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
+				commit, _, context, state, _, description := github.UpdateCommitStatusArgsForCall(0)
+				assert.Equal(t, version.Ref, commit)
+				assert.Equal(t, "semantic-title", context)
+				assert.Equal(t, tc.expectedState, state)
+				assert.Equal(t, tc.expectedDescription, description)
+			}
+		})
+	}
+}
+
+func TestPutDCO(t *testing.T) {
+	commit := func(sha, login, email, message string) models.CommitObject {
+		var c models.CommitObject
+		c.OID = sha
+		c.Author.User.Login = login
+		c.Author.Email = email
+		c.Message = message
+		return c
+	}
+
+	tests := []struct {
+		description         string
+		parameters          pr.DCOParameters
+		commits             []models.CommitObject
+		expectedState       string
+		expectedDescription string
+	}{
+		{
+			description: "all commits signed off by their author succeed",
+			commits: []models.CommitObject{
+				commit("1111111aaa", "alice", "alice@example.com", "fix: a\n\nSigned-off-by: Alice <Alice@example.com>"),
+				commit("2222222bbb", "bob", "bob@example.com", "fix: b\n\nSigned-off-by: Alice <alice@example.com>\nSigned-off-by: Bob <bob@example.com>"),
+			},
+			expectedState:       "success",
+			expectedDescription: "All commits are signed off",
+		},
+
+		{
+			description: "commits without a matching sign-off fail",
+			commits: []models.CommitObject{
+				commit("1111111aaa", "alice", "alice@example.com", "fix: a"),
+				commit("2222222bbb", "bob", "bob@example.com", "fix: b\n\nSigned-off-by: Alice <alice@example.com>"),
+				commit("3333333ccc", "carol", "carol@example.com", "fix: c\n\nSigned-off-by: Carol <carol@example.com>"),
+			},
+			expectedState:       "failure",
+			expectedDescription: "Commits without a Signed-off-by of their author: 1111111, 2222222",
+		},
+
+		{
+			description: "bots and exempt authors are skipped",
+			parameters:  pr.DCOParameters{ExemptBots: true, ExemptAuthors: []string{"ci@example.com"}},
+			commits: []models.CommitObject{
+				commit("1111111aaa", "", "49699333+dependabot[bot]@users.noreply.github.com", "build: bump"),
+				commit("2222222bbb", "", "ci@example.com", "chore: format"),
+			},
+			expectedState:       "success",
+			expectedDescription: "All commits are signed off",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			version := pr.Version{Ref: "commit1"}
+			github, git, dir := getForPut(t, test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), version)
+			github.ListPullRequestCommitsReturns(tc.commits, nil)
+
+			params := pr.PutParameters{DCO: &tc.parameters}
+			output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+			require.NoError(t, err)
+			assert.Contains(t, output.Metadata, &models.MetadataField{Name: "dco", Value: tc.expectedState})
+
+			if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
+				commit, _, context, state, _, description := github.UpdateCommitStatusArgsForCall(0)
+				assert.Equal(t, version.Ref, commit)
+				assert.Equal(t, "dco", context)
+				assert.Equal(t, tc.expectedState, state)
+				assert.Equal(t, tc.expectedDescription, description)
+			}
+		})
+	}
+}
+
+func TestPutCoverage(t *testing.T) {
+	goProfile := `mode: set
+github.com/itsdalmo/test-repository/pkg/a.go:3.10,5.2 3 1
+github.com/itsdalmo/test-repository/pkg/a.go:7.10,9.2 1 0
+github.com/itsdalmo/test-repository/pkg/b.go:3.10,5.2 4 1
+`
+	goBaseline := `mode: set
+github.com/itsdalmo/test-repository/pkg/a.go:3.10,5.2 3 0
+github.com/itsdalmo/test-repository/pkg/a.go:7.10,9.2 1 0
+github.com/itsdalmo/test-repository/pkg/b.go:3.10,5.2 4 1
+`
+	// Blocks appear once per test binary with -coverpkg.
+	goMergedProfile := `mode: set
+github.com/itsdalmo/test-repository/pkg/a.go:3.10,5.2 3 0
+github.com/itsdalmo/test-repository/pkg/a.go:7.10,9.2 1 0
+github.com/itsdalmo/test-repository/pkg/a.go:3.10,5.2 3 1
+github.com/itsdalmo/test-repository/pkg/a.go:7.10,9.2 1 0
+`
+	// main.go and cmd/x/main.go share a suffix.
+	goNestedProfile := `mode: set
+github.com/itsdalmo/test-repository/main.go:3.10,5.2 1 1
+github.com/itsdalmo/test-repository/cmd/x/main.go:3.10,5.2 3 0
+`
+	cobertura := `<?xml version="1.0" ?>
+<coverage line-rate="0.5">
+  <packages>
+    <package name="pkg">
+      <classes>
+        <class name="a" filename="pkg/a.py">
+          <lines><line number="1" hits="1"/><line number="2" hits="0"/></lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>
+`
+	lcov := `TN:
+SF:/build/src/pkg/a.ts
+DA:1,1
+DA:2,1
+DA:3,0
+DA:4,1
+end_of_record
+`
+	lcovPipe := `TN:
+SF:/build/src/pkg/a|b.ts
+DA:1,1
+DA:2,0
+end_of_record
+`
+
+	tests := []struct {
+		description         string
+		files               map[string]string
+		parameters          pr.CoverageParameters
+		changedFiles        []string
+		expectedState       string
+		expectedDescription string
+		expectedComment     string
+		expectedMetadata    []*models.MetadataField
+	}{
+		{
+			description:         "we report the delta of a go cover profile to the baseline",
+			files:               map[string]string{"coverage/cover.out": goProfile, "baseline/cover.out": goBaseline},
+			parameters:          pr.CoverageParameters{File: "coverage/cover.out", BaselineFile: "baseline/cover.out"},
+			expectedState:       "success",
+			expectedDescription: "87.5% covered (+37.5%)",
+			expectedComment:     "### Coverage\n\nTotal: **87.5%** (+37.5%)\n\n| Changed file | Coverage | Delta |\n|---|---|---|\n| `pkg/a.go` | 75.0% | +75.0% |\n",
+			expectedMetadata: []*models.MetadataField{
+				{Name: "coverage", Value: "87.5"},
+				{Name: "coverage_delta", Value: "+37.5%"},
+			},
+		},
+
+		{
+			description:         "we count blocks that appear more than once in a go cover profile once",
+			files:               map[string]string{"coverage/cover.out": goMergedProfile},
+			parameters:          pr.CoverageParameters{File: "coverage/cover.out"},
+			expectedState:       "success",
+			expectedDescription: "75.0% covered",
+			expectedComment:     "### Coverage\n\nTotal: **75.0%**\n\n| Changed file | Coverage |\n|---|---|\n| `pkg/a.go` | 75.0% |\n",
+			expectedMetadata: []*models.MetadataField{
+				{Name: "coverage", Value: "75.0"},
+			},
+		},
+
+		{
+			description:         "we match changed files to the closest file in the report",
+			files:               map[string]string{"coverage/cover.out": goNestedProfile},
+			parameters:          pr.CoverageParameters{File: "coverage/cover.out"},
+			changedFiles:        []string{"main.go", "cmd/x/main.go"},
+			expectedState:       "success",
+			expectedDescription: "25.0% covered",
+			expectedComment:     "### Coverage\n\nTotal: **25.0%**\n\n| Changed file | Coverage |\n|---|---|\n| `cmd/x/main.go` | 0.0% |\n| `main.go` | 100.0% |\n",
+			expectedMetadata: []*models.MetadataField{
+				{Name: "coverage", Value: "25.0"},
+			},
+		},
+
+		{
+			description:         "we fail the status when coverage is below the threshold",
+			files:               map[string]string{"coverage/coverage.xml": cobertura},
+			parameters:          pr.CoverageParameters{File: "coverage/coverage.xml", Threshold: 80},
+			expectedState:       "failure",
+			expectedDescription: "50.0% covered, below the threshold of 80%",
+			expectedComment:     "### Coverage\n\nTotal: **50.0%**\n",
+			expectedMetadata: []*models.MetadataField{
+				{Name: "coverage", Value: "50.0"},
+			},
+		},
+
+		{
+			description:         "we match changed files by suffix in lcov reports",
+			files:               map[string]string{"coverage/lcov.info": lcov},
+			parameters:          pr.CoverageParameters{File: "coverage/lcov.info", Threshold: 75},
+			expectedState:       "success",
+			expectedDescription: "75.0% covered",
+			expectedComment:     "### Coverage\n\nTotal: **75.0%**\n\n| Changed file | Coverage |\n|---|---|\n| `pkg/a.ts` | 75.0% |\n",
+			expectedMetadata: []*models.MetadataField{
+				{Name: "coverage", Value: "75.0"},
+			},
+		},
+
+		{
+			description:         "we escape pipes in the paths of changed files",
+			files:               map[string]string{"coverage/lcov.info": lcovPipe},
+			parameters:          pr.CoverageParameters{File: "coverage/lcov.info"},
+			changedFiles:        []string{"pkg/a|b.ts"},
+			expectedState:       "success",
+			expectedDescription: "50.0% covered",
+			expectedComment:     "### Coverage\n\nTotal: **50.0%**\n\n| Changed file | Coverage |\n|---|---|\n| `pkg/a\\|b.ts` | 50.0% |\n",
+			expectedMetadata: []*models.MetadataField{
+				{Name: "coverage", Value: "50.0"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			version := pr.Version{Ref: "commit1"}
+			github, git, dir := getForPut(t, test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), version)
+			changedFiles := tc.changedFiles
+			if changedFiles == nil {
+				changedFiles = []string{"pkg/a.go", "pkg/a.ts", "README.md"}
+			}
+			github.ListModifiedFilesReturns(changedFiles, nil)
+
+			for name, content := range tc.files {
+				require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), os.ModePerm))
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+			}
+
+			params := pr.PutParameters{Coverage: &tc.parameters}
+			output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+			require.NoError(t, err)
+			for _, m := range tc.expectedMetadata {
+				assert.Contains(t, output.Metadata, m)
+			}
+
+			if assert.Equal(t, 1, github.UpdateCommitStatusCallCount()) {
+				_, _, context, state, _, description := github.UpdateCommitStatusArgsForCall(0)
+				assert.Equal(t, "coverage", context)
+				assert.Equal(t, tc.expectedState, state)
+				assert.Equal(t, tc.expectedDescription, description)
+			}
+			if assert.Equal(t, 1, github.UpsertCommentCallCount()) {
+				number, marker, comment := github.UpsertCommentArgsForCall(0)
+				assert.Equal(t, 1, number)
+				assert.Equal(t, "<!-- concourse-ci:coverage -->", marker)
+				assert.Equal(t, tc.expectedComment, comment)
+			}
+		})
+	}
+}
+
+func TestPutLabeler(t *testing.T) {
+	parameters := pr.LabelerParameters{
+		Paths: map[string][]string{
+			"docs":     {"docs", "*.md"},
+			"frontend": {"web/*.ts"},
+			"backend":  {"cmd", "models"},
+		},
+		Sizes: map[string]int{"size/S": 0, "size/M": 30, "size/L": 100},
+	}
+
+	tests := []struct {
+		description     string
+		files           []string
+		additions       int
+		deletions       int
+		labels          []string
+		expectedAdded   []string
+		expectedRemoved []string
+	}{
+		{
+			description:   "we add labels for matching paths and the size",
+			files:         []string{"README.md", "models/github.go"},
+			additions:     20,
+			deletions:     20,
+			labels:        []string{"bug"},
+			expectedAdded: []string{"backend", "docs", "size/M"},
+		},
+
+		{
+			description:     "we remove managed labels that no longer match",
+			files:           []string{"web/index.ts"},
+			additions:       150,
+			labels:          []string{"bug", "docs", "frontend", "size/M"},
+			expectedAdded:   []string{"size/L"},
+			expectedRemoved: []string{"docs", "size/M"},
+		},
+
+		{
+			description: "we do nothing when the labels are up to date",
+			files:       []string{"docs/usage.txt"},
+			additions:   1,
+			labels:      []string{"docs", "size/S"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			version := pr.Version{Ref: "commit1"}
+			pull := test_helpers.CreateTestPR(1, "master", false, false, 0, tc.labels, false, githubv4.PullRequestStateOpen)
+			pull.Additions = tc.additions
+			pull.Deletions = tc.deletions
+
+			github, git, dir := getForPut(t, pull, version)
+			github.ListModifiedFilesReturns(tc.files, nil)
+
+			params := pr.PutParameters{Labeler: &parameters}
+			_, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+			require.NoError(t, err)
+
+			if len(tc.expectedAdded) == 0 {
+				assert.Equal(t, 0, github.AddLabelsCallCount())
+			} else if assert.Equal(t, 1, github.AddLabelsCallCount()) {
+				number, labels := github.AddLabelsArgsForCall(0)
+				assert.Equal(t, 1, number)
+				assert.Equal(t, tc.expectedAdded, labels)
+			}
+
+			var removed []string
+			for i := 0; i < github.RemoveLabelCallCount(); i++ {
+				_, label := github.RemoveLabelArgsForCall(i)
+				removed = append(removed, label)
+			}
+			assert.Equal(t, tc.expectedRemoved, removed)
+		})
+	}
+}
+
+func TestPutStatusSummary(t *testing.T) {
+	version := pr.Version{Ref: "commit1"}
+	started := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	github, git, dir := getForPut(t, test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), version)
+	github.ListCommitChecksReturns([]models.CommitCheck{
+		{Name: "concourse-ci/unit", State: "success", URL: "https://ci.example.com/builds/1", StartedAt: started, CompletedAt: started.Add(90 * time.Second)},
+		{Name: "build", State: "in_progress", URL: "https://github.com/runs/2", StartedAt: started},
+		{Name: "concourse-ci/lint", State: "failure", StartedAt: started, CompletedAt: started},
+		{Name: "e2e | chrome\nretried", State: "failure"},
+	}, nil)
+
+	params := pr.PutParameters{StatusSummary: &pr.StatusSummaryParameters{Title: "CI\nstatus"}}
+	_, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+	require.NoError(t, err)
+
+	if assert.Equal(t, 1, github.ListCommitChecksCallCount()) {
+		assert.Equal(t, version.Ref, github.ListCommitChecksArgsForCall(0))
+	}
+	if assert.Equal(t, 1, github.UpsertCommentCallCount()) {
+		number, marker, comment := github.UpsertCommentArgsForCall(0)
+		assert.Equal(t, 1, number)
+		assert.Equal(t, "<!-- concourse-ci:status-summary -->", marker)
+		assert.Equal(t, `### CI status
+
+For commit commit1:
+
+| Context | State | Link | Duration |
+|---|---|---|---|
+| build | in_progress | [details](https://github.com/runs/2) |  |
+| concourse-ci/lint | failure |  |  |
+| concourse-ci/unit | success | [details](https://ci.example.com/builds/1) | 1m30s |
+| e2e \| chrome retried | failure |  |  |
+`, comment)
+	}
+}
+
+func TestPutActionOutcomes(t *testing.T) {
+	version := pr.Version{Ref: "commit1"}
+
+	setup := func(t *testing.T, state githubv4.PullRequestState) (*fakes.FakeGithub, *fakes.FakeGit, string) {
+		return getForPut(t, test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, state), version)
+	}
+
+	t.Run("the error lists the actions that completed", func(t *testing.T) {
+		github, git, dir := setup(t, githubv4.PullRequestStateOpen)
+		github.UpsertCommentReturns(errors.New("boom"))
+
+		params := pr.PutParameters{Status: "success", Comment: "done", Labeler: &pr.LabelerParameters{}}
+		_, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+		assert.EqualError(t, err, "failed to post comment: boom (completed actions: status)")
+		assert.Equal(t, 0, github.ListModifiedFilesCallCount())
+	})
+
+	t.Run("we carry on after failures with continue_on_error", func(t *testing.T) {
+		github, git, dir := setup(t, githubv4.PullRequestStateOpen)
+		github.UpdateCommitStatusReturns(errors.New("boom"))
+
+		params := pr.PutParameters{ContinueOnError: true, Status: "success", Comment: "done"}
+		output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+		require.NoError(t, err)
+		assert.Equal(t, 1, github.UpsertCommentCallCount())
+		assert.Contains(t, output.Metadata, &models.MetadataField{Name: "action_status", Value: "failed: failed to set status: boom"})
+		assert.Contains(t, output.Metadata, &models.MetadataField{Name: "action_comment", Value: "success"})
+	})
+
+	t.Run("we do not backport after a failed merge with continue_on_error", func(t *testing.T) {
+		github, git, dir := setup(t, githubv4.PullRequestStateOpen)
+		github.MergePullRequestReturns("", errors.New("boom"))
+
+		params := pr.PutParameters{
+			ContinueOnError: true,
+			Merge:           &pr.MergeParameters{},
+			Backport:        &pr.BackportParameters{Branches: []string{"release-1.x"}},
+		}
+		output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+		require.NoError(t, err)
+		assert.Equal(t, 0, git.CherryPickCallCount())
+		assert.Contains(t, output.Metadata, &models.MetadataField{Name: "action_backport", Value: "failed: failed to backport: pull request is not merged"})
+	})
+
+	t.Run("a retry edits the comment of the first attempt", func(t *testing.T) {
+		github, git, dir := setup(t, githubv4.PullRequestStateOpen)
+
+		params := pr.PutParameters{Comment: "done"}
+		for i := 0; i < 2; i++ {
+			_, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+			require.NoError(t, err)
+		}
+		if assert.Equal(t, 2, github.UpsertCommentCallCount()) {
+			_, first, _ := github.UpsertCommentArgsForCall(0)
+			_, second, _ := github.UpsertCommentArgsForCall(1)
+			assert.Equal(t, first, second)
+		}
+	})
+
+	t.Run("a retry does not merge again", func(t *testing.T) {
+		github, git, dir := setup(t, githubv4.PullRequestStateMerged)
+
+		params := pr.PutParameters{Merge: &pr.MergeParameters{DeleteBranch: true}}
+		output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+		require.NoError(t, err)
+		assert.Equal(t, 0, github.MergePullRequestCallCount())
+		assert.Equal(t, 1, github.DeleteBranchCallCount())
+		assert.Contains(t, output.Metadata, &models.MetadataField{Name: "merge_sha", Value: "already merged"})
+	})
+}
+
+func TestPutDryRun(t *testing.T) {
+	version := pr.Version{Ref: "commit1"}
+	github, git, dir := getForPut(t, test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen), version)
+	github.ListModifiedFilesReturns([]string{"README.md"}, nil)
+	github.GetPullRequestHeadSHAReturns(version.Ref, nil)
+
+	params := pr.PutParameters{
+		DryRun:     true,
+		Status:     "success",
+		Comment:    "Tested $BUILD_ID",
+		Labeler:    &pr.LabelerParameters{Paths: map[string][]string{"docs": {"*.md"}}},
+		Deployment: &pr.DeploymentParameters{State: "success"},
+		Merge:      &pr.MergeParameters{Method: "squash", DeleteBranch: true},
+	}
+	output, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+	require.NoError(t, err)
+
+	// Read-only requests are made (in addition to the one by get)...
+	assert.Equal(t, 2, github.GetPullRequestCallCount())
+	assert.Equal(t, 1, github.ListModifiedFilesCallCount())
+
+	// ...but nothing is changed.
+	assert.Equal(t, 0, github.UpdateCommitStatusCallCount())
+	assert.Equal(t, 0, github.UpsertCommentCallCount())
+	assert.Equal(t, 0, github.AddLabelsCallCount())
+	assert.Equal(t, 0, github.CreateDeploymentCallCount())
+	assert.Equal(t, 0, github.CreateDeploymentStatusCallCount())
+	assert.Equal(t, 0, github.MergePullRequestCallCount())
+	assert.Equal(t, 0, github.DeleteBranchCallCount())
+
+	assert.Contains(t, output.Metadata, &models.MetadataField{Name: "dry_run", Value: "true"})
+	assert.Contains(t, output.Metadata, &models.MetadataField{Name: "action_status", Value: "planned: set status concourse-ci/status to success on commit1"})
+	assert.Contains(t, output.Metadata, &models.MetadataField{Name: "action_labeler", Value: "planned: add labels to #1: docs"})
+	assert.Contains(t, output.Metadata, &models.MetadataField{Name: "action_merge", Value: "planned: merge #1 at commit1 using squash; delete branch pr1"})
+
+	// Pushes are planned as well, and can not be combined with a merge.
+	params = pr.PutParameters{DryRun: true, Push: &pr.PushParameters{Repository: "formatted", Message: "Format code"}}
+	pushOutput, err := pr.Put(pr.PutRequest{Source: putSource, Params: params}, github, git, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 0, git.CommitCallCount())
+	assert.Equal(t, 0, git.PushCallCount())
+	assert.Contains(t, pushOutput.Metadata, &models.MetadataField{Name: "action_push", Value: "planned: commit changes as concourse-ci <concourse@local>: \"Format code\\n\\n" + models.PushedCommitTrailer + "\"; push to branch pr1"})
+
+	// The results of changes that were not made are not recorded.
+	for _, m := range append(output.Metadata, pushOutput.Metadata...) {
+		assert.NotContains(t, []string{"pushed_sha", "merge_sha"}, m.Name)
+	}
+}
@@ -0,0 +1,64 @@
+package pr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+)
+
+// statusSummaryMarker identifies the status summary comment, which is updated
+// by later puts instead of posting a new one.
+const statusSummaryMarker = "<!-- concourse-ci:status-summary -->"
+
+// tableCellReplacer escapes the pipes and line breaks that would otherwise end
+// a cell or row of a markdown table.
+var tableCellReplacer = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+// tableCell escapes s for use in a cell of a markdown table.
+func tableCell(s string) string {
+	return tableCellReplacer.Replace(s)
+}
+
+// statusSummaryComment renders a table of the statuses and check runs on a commit.
+func statusSummaryComment(title, commitRef string, checks []models.CommitCheck) string {
+	if title == "" {
+		title = "CI status"
+	}
+	// A line break would end the heading.
+	title = strings.Join(strings.Fields(title), " ")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s\n\nFor commit %s:\n\n", title, commitRef)
+	if len(checks) == 0 {
+		b.WriteString("No statuses or checks have been reported yet.\n")
+		return b.String()
+	}
+
+	sorted := make([]models.CommitCheck, len(checks))
+	copy(sorted, checks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	b.WriteString("| Context | State | Link | Duration |\n|---|---|---|---|\n")
+	for _, c := range sorted {
+		link := ""
+		if c.URL != "" {
+			link = fmt.Sprintf("[details](%s)", tableCell(c.URL))
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", tableCell(c.Name), tableCell(c.State), link, checkDuration(c))
+	}
+	return b.String()
+}
+
+// checkDuration returns the duration of a check that has completed, or an
+// empty string if it is not known.
+func checkDuration(c models.CommitCheck) string {
+	if isPendingCheck(c.State) || c.StartedAt.IsZero() || !c.CompletedAt.After(c.StartedAt) {
+		return ""
+	}
+	return c.CompletedAt.Sub(c.StartedAt).Round(time.Second).String()
+}
@@ -0,0 +1,57 @@
+package pr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultSemanticTypes are the types recommended by Conventional Commits.
+var DefaultSemanticTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+var semanticTitleRegexp = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?: (.+)$`)
+
+// maxStatusDescription is the maximum length of a commit status description.
+const maxStatusDescription = 140
+
+// checkSemanticTitle returns a description of what is wrong with the title, or
+// an empty string if it follows Conventional Commits and the given parameters.
+func checkSemanticTitle(title string, p *SemanticTitleParameters) string {
+	match := semanticTitleRegexp.FindStringSubmatch(strings.TrimSpace(title))
+	if match == nil {
+		return "Title must look like 'type(scope): subject'"
+	}
+	kind, scope, subject := match[1], match[2], match[4]
+
+	types := p.Types
+	if len(types) == 0 {
+		types = DefaultSemanticTypes
+	}
+	if !containsString(types, kind) {
+		return fmt.Sprintf("Unknown type '%s', use one of: %s", kind, strings.Join(types, ", "))
+	}
+
+	if scope == "" {
+		if p.RequireScope {
+			return "Title must have a scope, e.g. 'type(scope): subject'"
+		}
+	} else if len(p.Scopes) > 0 && !containsString(p.Scopes, scope) {
+		return fmt.Sprintf("Unknown scope '%s', use one of: %s", scope, strings.Join(p.Scopes, ", "))
+	}
+
+	// The pattern is validated along with the other parameters.
+	if p.Pattern != "" && !regexp.MustCompile(p.Pattern).MatchString(subject) {
+		return fmt.Sprintf("Subject must match %s", p.Pattern)
+	}
+	return ""
+}
+
+// truncateDescription shortens s to fit in a commit status description. The
+// limit is in characters, so s is cut between runes.
+func truncateDescription(s string) string {
+	if utf8.RuneCountInString(s) <= maxStatusDescription {
+		return s
+	}
+	return string([]rune(s)[:maxStatusDescription-3]) + "..."
+}
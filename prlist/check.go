@@ -2,138 +2,200 @@ package prlist
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
-	"github.com/shurcooL/githubv4"
 )
 
 func Check(request CheckRequest, manager models.Github) (CheckResponse, error) {
 	var pulls []*models.PullRequest
 	var err error
 	// Filter out pull request if it does not have a filtered state
-	filterStates := []githubv4.PullRequestState{githubv4.PullRequestStateOpen}
-	if len(request.Source.States) > 0 {
-		filterStates = request.Source.States
-	}
-
-	pulls, err = manager.ListPullRequests(filterStates)
+	pulls, err = manager.ListPullRequests(request.Source.states())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get last commits: %s", err)
 	}
 
 	disableSkipCI := request.Source.DisableCISkip
 
+	filter, err := newPRFilter(request.Source, manager)
+	if err != nil {
+		return nil, err
+	}
+
 	var validPRs []*models.PullRequest
-Loop:
 	for _, p := range pulls {
 		// [ci skip]/[skip ci] in Pull request title
 		if !disableSkipCI && ContainsSkipCI(p.Title) {
 			continue
 		}
 
-		// Filter pull request if the BaseBranch does not match the one specified in source
-		if request.Source.BaseBranch != "" {
-			// Occasionally, github will prefix the baseRefName with
-			// refs/heads/ rather than just using the branch name itself - not
-			// sure when/why this happens.
-			baseBranch := strings.TrimPrefix(p.PullRequestObject.BaseRefName, "refs/heads/")
-			if baseBranch != request.Source.BaseBranch {
-				continue
-			}
+		ok, err := filter.matches(p)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			validPRs = append(validPRs, p)
 		}
+	}
 
-		// Filter out pull request if it does not contain at least one of the desired labels
-		if len(request.Source.Labels) > 0 {
-			labelFound := false
+	version := NewVersion(validPRs)
 
-		LabelLoop:
-			for _, wantedLabel := range request.Source.Labels {
-				for _, targetLabel := range p.Labels {
-					if targetLabel.Name == wantedLabel {
-						labelFound = true
-						break LabelLoop
-					}
-				}
-			}
+	if request.Version == nil {
+		return CheckResponse{version}, nil
+	}
 
-			if !labelFound {
-				continue Loop
-			}
-		}
+	if version.PRs == request.Version.PRs {
+		return CheckResponse{*request.Version}, nil
+	}
 
-		// Filter out forks.
-		if request.Source.DisableForks && p.IsCrossRepository {
-			continue
-		}
+	return CheckResponse{*request.Version, version}, nil
+}
 
-		// Filter out drafts.
-		if request.Source.IgnoreDrafts && p.IsDraft {
-			continue
-		}
+// prFilter applies the filters of a source to pull requests, except for
+// [ci skip] in their title.
+type prFilter struct {
+	source     Source
+	manager    models.Github
+	expression labelExpression
+}
 
-		// Filter pull request if it does not have the required number of approved review(s).
-		if p.ApprovedReviewCount < request.Source.RequiredReviewApprovals {
-			continue
+func newPRFilter(source Source, manager models.Github) (*prFilter, error) {
+	f := &prFilter{source: source, manager: manager}
+	if source.LabelExpression != "" {
+		expression, err := parseLabelExpression(source.LabelExpression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label_expression: %s", err)
 		}
+		f.expression = expression
+	}
+	return f, nil
+}
 
-		// Haven't yet checked the PR for whether it satisfies the modified
-		// files criteria.
+// matches returns true if the pull request passes all filters.
+func (f *prFilter) matches(p *models.PullRequest) (bool, error) {
+	source := f.source
 
-		// Fetch files once if paths/ignore_paths are specified.
-		var files []string
+	// Filter pull request if the BaseBranch does not match the one specified in source
+	if !source.matchesBaseBranch(p) {
+		return false, nil
+	}
 
-		if len(request.Source.Paths) > 0 || len(request.Source.IgnorePaths) > 0 {
-			files, err = manager.ListModifiedFiles(p.Number)
-			if err != nil {
-				return nil, fmt.Errorf("failed to list modified files: %s", err)
-			}
+	// Filter pull request if the head branch is not one of the desired branches
+	if !source.matchesHeadBranch(p) {
+		return false, nil
+	}
+
+	// Filter out pull request if it does not contain the desired labels
+	if !source.matchesLabels(p) {
+		return false, nil
+	}
+
+	// Filter out pull request if its labels do not satisfy the expression
+	if f.expression != nil {
+		labels := make(map[string]bool, len(p.Labels))
+		for _, l := range p.Labels {
+			labels[l.Name] = true
+		}
+		if !f.expression.eval(labels) {
+			return false, nil
 		}
+	}
 
-		// Skip version if no files match the specified paths.
-		if len(request.Source.Paths) > 0 {
-			var wanted []string
-			for _, pattern := range request.Source.Paths {
-				w, err := FilterPath(files, pattern)
-				if err != nil {
-					return nil, fmt.Errorf("path match failed: %s", err)
-				}
-				wanted = append(wanted, w...)
-			}
-			if len(wanted) == 0 {
-				continue Loop
-			}
+	// Filter out pull request if it has any of the ignored labels
+	if len(source.IgnoreLabels) > 0 {
+		ignored, err := HasMatchingLabel(p.Labels, source.IgnoreLabels)
+		if err != nil {
+			return false, fmt.Errorf("ignore label match failed: %s", err)
+		}
+		if ignored {
+			return false, nil
 		}
+	}
 
-		// Skip version if all files are ignored.
-		if len(request.Source.IgnorePaths) > 0 {
-			wanted := files
-			for _, pattern := range request.Source.IgnorePaths {
-				wanted, err = FilterIgnorePath(wanted, pattern)
-				if err != nil {
-					return nil, fmt.Errorf("ignore path match failed: %s", err)
-				}
-			}
-			if len(wanted) == 0 {
-				continue Loop
-			}
+	// Filter out forks.
+	if source.DisableForks && p.IsCrossRepository {
+		return false, nil
+	}
+
+	// Filter out pull request if it is not from one of the desired authors,
+	// associations or fork owners.
+	if !source.matchesAuthor(p) {
+		return false, nil
+	}
+
+	// Filter out PRs from untrusted forks, unless their head was approved for testing.
+	if source.RequireOkToTest && models.RequiresOkToTest(p.PullRequestObject) {
+		okToTest, err := f.manager.GetOkToTest(p.Number, source.okToTestLabel())
+		if err != nil {
+			return false, fmt.Errorf("failed to get ok-to-test approvals: %s", err)
+		}
+		// Shown in the check logs, since the approval seemingly has no effect.
+		if warning := okToTest.Warning(p.Number); warning != "" {
+			fmt.Fprintln(os.Stderr, warning)
+		}
+		if !okToTest.Allows(p.Tip.OID) {
+			return false, nil
 		}
-		validPRs = append(validPRs, p)
 	}
 
-	version := NewVersion(validPRs)
+	// Filter out drafts.
+	if source.IgnoreDrafts && p.IsDraft {
+		return false, nil
+	}
 
-	if request.Version == nil {
-		return CheckResponse{version}, nil
+	// Filter pull request if it does not have the required number of approved review(s).
+	if p.ApprovedReviewCount < source.RequiredReviewApprovals {
+		return false, nil
 	}
 
-	if version.PRs == request.Version.PRs {
-		return CheckResponse{*request.Version}, nil
+	// Haven't yet checked the PR for whether it satisfies the modified
+	// files criteria.
+
+	// Fetch files once if paths/ignore_paths are specified.
+	var files []string
+
+	if len(source.Paths) > 0 || len(source.IgnorePaths) > 0 {
+		var err error
+		files, err = f.manager.ListModifiedFiles(p.Number)
+		if err != nil {
+			return false, fmt.Errorf("failed to list modified files: %s", err)
+		}
 	}
 
-	return CheckResponse{*request.Version, version}, nil
+	// Skip version if no files match the specified paths.
+	if len(source.Paths) > 0 {
+		var wanted []string
+		for _, pattern := range source.Paths {
+			w, err := FilterPath(files, pattern)
+			if err != nil {
+				return false, fmt.Errorf("path match failed: %s", err)
+			}
+			wanted = append(wanted, w...)
+		}
+		if len(wanted) == 0 {
+			return false, nil
+		}
+	}
+
+	// Skip version if all files are ignored.
+	if len(source.IgnorePaths) > 0 {
+		wanted := files
+		for _, pattern := range source.IgnorePaths {
+			var err error
+			wanted, err = FilterIgnorePath(wanted, pattern)
+			if err != nil {
+				return false, fmt.Errorf("ignore path match failed: %s", err)
+			}
+		}
+		if len(wanted) == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
 // ContainsSkipCI returns true if a string contains [ci skip] or [skip ci].
@@ -172,6 +234,79 @@ func FilterPath(files []string, pattern string) ([]string, error) {
 	return out, nil
 }
 
+// MatchPattern returns true if s matches the pattern, which is a regular
+// expression when enclosed in slashes (e.g. /^wip/), and a glob otherwise.
+// In globs, ** also matches across slashes (e.g. dependabot/**).
+func MatchPattern(pattern, s string) (bool, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		return regexp.MatchString(pattern[1:len(pattern)-1], s)
+	}
+	if strings.Contains(pattern, "**") {
+		// Match each part between ** as a glob, and let ** match anything in between.
+		parts := strings.Split(pattern, "**")
+		var b strings.Builder
+		b.WriteString("^")
+		for i, part := range parts {
+			if _, err := filepath.Match(part, ""); err != nil {
+				return false, err
+			}
+			if i > 0 {
+				b.WriteString(".*")
+			}
+			b.WriteString(globToRegexp(part))
+		}
+		b.WriteString("$")
+		return regexp.MatchString(b.String(), s)
+	}
+	return filepath.Match(pattern, s)
+}
+
+// globToRegexp translates a glob without ** to a regular expression.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			// Character classes have the same syntax in both.
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(glob[i:]))
+				return b.String()
+			}
+			b.WriteString(glob[i : i+end+1])
+			i += end
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+			}
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// HasMatchingLabel returns true if any of the labels matches one of the patterns.
+func HasMatchingLabel(labels []models.LabelObject, patterns []string) (bool, error) {
+	for _, pattern := range patterns {
+		for _, l := range labels {
+			match, err := MatchPattern(pattern, l.Name)
+			if err != nil {
+				return false, err
+			}
+			if match {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 // IsInsidePath checks whether the child path is inside the parent path.
 //
 // /foo/bar is inside /foo, but /foobar is not inside /foo.
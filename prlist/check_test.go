@@ -29,6 +29,25 @@ var (
 	}
 )
 
+// authoredPullRequests are opened by (1) a member, (2) the owner, (3) a bot,
+// (4) a contributor from a partner fork, and (5) a contributor from another fork.
+var authoredPullRequests = []*models.PullRequest{
+	authoredPR(1, "alice", "User", githubv4.CommentAuthorAssociationMember, ""),
+	authoredPR(2, "bob", "User", githubv4.CommentAuthorAssociationOwner, ""),
+	authoredPR(3, "dependabot", "Bot", githubv4.CommentAuthorAssociationNone, ""),
+	authoredPR(4, "carol", "User", githubv4.CommentAuthorAssociationContributor, "partner"),
+	authoredPR(5, "dave", "User", githubv4.CommentAuthorAssociationFirstTimeContributor, "stranger"),
+}
+
+func authoredPR(number int, login, typename string, association githubv4.CommentAuthorAssociation, forkOwner string) *models.PullRequest {
+	p := test_helpers.CreateTestPR(number, "master", false, forkOwner != "", 0, nil, false, githubv4.PullRequestStateOpen)
+	p.Author.Login = login
+	p.Author.Typename = typename
+	p.AuthorAssociation = association
+	p.HeadRepository.Owner.Login = forkOwner
+	return p
+}
+
 func TestCheck(t *testing.T) {
 	tests := []struct {
 		description  string
@@ -36,6 +55,7 @@ func TestCheck(t *testing.T) {
 		version      *prlist.Version
 		files        [][]string
 		pullRequests []*models.PullRequest
+		okToTest     *models.OkToTest
 		expected     prlist.CheckResponse
 	}{
 		{
@@ -275,6 +295,339 @@ func TestCheck(t *testing.T) {
 			},
 		},
 
+		{
+			description: "check returns latest version from a PR with all of the desired labels on it",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Labels:      []string{"enhancement", "wontfix"},
+				LabelsMatch: "all",
+			},
+			version: nil,
+			pullRequests: []*models.PullRequest{
+				testPullRequests[6],
+				testPullRequests[7],
+				test_helpers.CreateTestPR(13, "master", false, false, 0, []string{"enhancement", "wontfix"}, false, githubv4.PullRequestStateOpen),
+			},
+			files: [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[13]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check returns latest version from PRs whose labels satisfy the expression",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				LabelExpression: `("enhancement" || wontfix) && !(wip || blocked)`,
+			},
+			version:      nil,
+			pullRequests: testPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[7,8]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check returns latest version from PRs without labels of a negated expression",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				LabelExpression: "!wontfix && !enhancement",
+			},
+			version:      nil,
+			pullRequests: testPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[1,2,3,4,5,6,9,12]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check returns versions from PRs by the desired authors",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Authors: []string{"Alice", "dependabot[bot]"},
+			},
+			version:      nil,
+			pullRequests: authoredPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[1,3]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check skips PRs by ignored authors",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				IgnoreAuthors: []string{"dependabot[bot]"},
+			},
+			version:      nil,
+			pullRequests: authoredPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[1,2,4,5]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check returns versions from PRs by authors with the desired associations",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				AuthorAssociations: []githubv4.CommentAuthorAssociation{githubv4.CommentAuthorAssociationMember, githubv4.CommentAuthorAssociationOwner},
+			},
+			version:      nil,
+			pullRequests: authoredPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[1,2]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check returns versions from PRs in forks of the desired owners",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				ForkOwners: []string{"partner"},
+			},
+			version:      nil,
+			pullRequests: authoredPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[1,2,3,4]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check allows PRs that match any of the author and fork filters",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				AuthorAssociations: []githubv4.CommentAuthorAssociation{githubv4.CommentAuthorAssociationMember},
+				ForkOwners:         []string{"partner"},
+				IgnoreAuthors:      []string{"bob"},
+			},
+			version:      nil,
+			pullRequests: authoredPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[1,3,4]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check allows PRs by the desired authors or associations",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Authors:            []string{"dave"},
+				AuthorAssociations: []githubv4.CommentAuthorAssociation{githubv4.CommentAuthorAssociationMember},
+			},
+			version:      nil,
+			pullRequests: authoredPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[1,5]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check returns versions from untrusted forks once their head is approved",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				RequireOkToTest: true,
+			},
+			version:      nil,
+			pullRequests: authoredPullRequests,
+			okToTest:     &models.OkToTest{Required: true, ApprovedCommits: []string{"oid4", "oid5-old"}},
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[1,2,3,4]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check returns versions from PRs against one of the base branches",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				BaseBranches: []string{"dev*", "release/*"},
+			},
+			version:      nil,
+			pullRequests: testPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[7]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check matches base branches by regular expression",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				BaseBranches: []string{"/^(master|develop)$/"},
+			},
+			version:      nil,
+			pullRequests: testPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[1,2,3,4,5,6,7,8,9,12]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check returns versions from PRs from one of the head branches",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				HeadBranches: []string{"pr1*"},
+			},
+			version:      nil,
+			pullRequests: testPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[1,12]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check skips PRs from ignored head branches",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				IgnoreHeadBranches: []string{"pr1*", "pr[2-4]"},
+			},
+			version:      nil,
+			pullRequests: testPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[5,6,7,8,9]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check skips PRs with any of the ignored labels",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				IgnoreLabels: []string{"wontfix", "do-not-build"},
+			},
+			version:      nil,
+			pullRequests: testPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[1,2,3,4,5,6,7,9,12]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check matches ignored labels by glob or regular expression",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				IgnoreLabels: []string{"wont*", "/^enh/"},
+			},
+			version:      nil,
+			pullRequests: testPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.Version{PRs: "[1,2,3,4,5,6,9,12]", Timestamp: time.Now().Format("2006-01-02 15:04:05")},
+			},
+		},
+
+		{
+			description: "check applies ignored labels after the desired labels",
+			source: prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				Labels:       []string{"enhancement", "wontfix"},
+				IgnoreLabels: []string{"wontfix"},
+			},
+			version:      nil,
+			pullRequests: testPullRequests,
+			files:        [][]string{},
+			expected: prlist.CheckResponse{
+				prlist.NewVersion([]*models.PullRequest{testPullRequests[6]}),
+			},
+		},
+
 		{
 			description: "check returns latest version from a PR with a single state filter",
 			source: prlist.Source{
@@ -351,6 +704,7 @@ func TestCheck(t *testing.T) {
 				}
 			}
 			github.ListPullRequestsReturns(pullRequests, nil)
+			github.GetOkToTestReturns(tc.okToTest, nil)
 
 			for i, file := range tc.files {
 				github.ListModifiedFilesReturnsOnCall(i, file, nil)
@@ -367,6 +721,76 @@ func TestCheck(t *testing.T) {
 	}
 }
 
+func TestValidateLabels(t *testing.T) {
+	cases := []struct {
+		description     string
+		labelsMatch     string
+		labelExpression string
+		expected        string
+	}{
+		{
+			description:     "accepts a valid expression",
+			labelExpression: `ready && !(wip || "on hold")`,
+		},
+		{
+			description: "rejects an unknown labels_match",
+			labelsMatch: "some",
+			expected:    `labels_match value "some" must be one of: any, all`,
+		},
+		{
+			description:     "rejects single operators",
+			labelExpression: "ready & wip",
+			expected:        `invalid label_expression: unexpected "&" at position 7, use "&&"`,
+		},
+		{
+			description:     "rejects missing operands",
+			labelExpression: "ready && || wip",
+			expected:        `invalid label_expression: expected a label name, "!" or "(", got "||" at position 10`,
+		},
+		{
+			description:     "rejects missing operators",
+			labelExpression: "ready wip",
+			expected:        `invalid label_expression: unexpected "wip" at position 7, expected && or ||`,
+		},
+		{
+			description:     "rejects unbalanced parentheses",
+			labelExpression: "ready && (wip || blocked",
+			expected:        `invalid label_expression: missing ")" for "(" at position 10, got end of expression`,
+		},
+		{
+			description:     "rejects unterminated quotes",
+			labelExpression: `ready && "on hold`,
+			expected:        "invalid label_expression: missing closing quote for label name at position 10",
+		},
+		{
+			description:     "rejects trailing operators",
+			labelExpression: "ready &&",
+			expected:        `invalid label_expression: expected a label name, "!" or "(", got end of expression`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			source := prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+				LabelsMatch:     tc.labelsMatch,
+				LabelExpression: tc.labelExpression,
+			}
+			err := source.Validate()
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
+
 func TestContainsSkipCI(t *testing.T) {
 	tests := []struct {
 		description string
@@ -620,3 +1044,37 @@ func TestIsInsidePath(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchPattern(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		s        string
+		expected bool
+	}{
+		{pattern: "wip", s: "wip", expected: true},
+		{pattern: "wip", s: "wip-2", expected: false},
+		{pattern: "on-*", s: "on-hold", expected: true},
+		{pattern: "/^do-not-/", s: "do-not-build", expected: true},
+		{pattern: "/^do-not-/", s: "please-do-not-build", expected: false},
+		{pattern: "/", s: "/", expected: true},
+		{pattern: "release/*", s: "release/1.x", expected: true},
+		{pattern: "release/*", s: "release/1.x/hotfix", expected: false},
+		{pattern: "dependabot/**", s: "dependabot/npm_and_yarn/lodash-4.17.21", expected: true},
+		{pattern: "dependabot/**", s: "renovate/lodash", expected: false},
+		{pattern: "**/hotfix-?", s: "release/1.x/hotfix-1", expected: true},
+		{pattern: "**/[a-c]*.x", s: "release/b1.x", expected: true},
+		{pattern: "**/[a-c]*.x", s: "release/d1.x", expected: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			match, err := prlist.MatchPattern(tc.pattern, tc.s)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, match)
+			}
+		})
+	}
+
+	_, err := prlist.MatchPattern("/(/", "")
+	assert.Error(t, err)
+}
@@ -2,20 +2,45 @@ package prlist
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
 )
 
-func Get(request GetRequest, outputDir string) (*GetResponse, error) {
+func Get(request GetRequest, github models.Github, outputDir string) (*GetResponse, error) {
 	path := filepath.Join(outputDir, "prs.json")
 	var prNumbers []int
 	if err := json.Unmarshal([]byte(request.Version.PRs), &prNumbers); err != nil {
 		return nil, err
 	}
 
+	// The version only contains the PR numbers, so look up the branches of
+	// each PR in a single request. PRs that are no longer in the selected
+	// states are listed without them.
+	var pulls []*models.PullRequest
+	if len(prNumbers) > 0 {
+		var err error
+		pulls, err = github.ListPullRequests(request.Source.states())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pull requests: %s", err)
+		}
+	}
+	pullsByNumber := make(map[int]*models.PullRequest, len(pulls))
+	for _, p := range pulls {
+		pullsByNumber[p.Number] = p
+	}
+
 	prs := make([]PRData, 0, len(prNumbers))
 	for _, prNumber := range prNumbers {
-		prs = append(prs, PRData{Number: prNumber})
+		data := PRData{Number: prNumber}
+		if p, ok := pullsByNumber[prNumber]; ok {
+			data.BaseName = strings.TrimPrefix(p.BaseRefName, "refs/heads/")
+			data.HeadName = p.HeadRefName
+		}
+		prs = append(prs, data)
 	}
 
 	payload, err := json.Marshal(prs)
@@ -27,6 +52,15 @@ func Get(request GetRequest, outputDir string) (*GetResponse, error) {
 		return nil, err
 	}
 
+	// Write the version for reuse in PUT
+	payload, err = json.Marshal(request.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(outputDir, "version.json"), payload, 0644); err != nil {
+		return nil, err
+	}
+
 	return &GetResponse{
 		Version: request.Version,
 	}, nil
@@ -0,0 +1,73 @@
+package prlist_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models/fakes"
+	"github.com/cloudfoundry-community/github-pr-instances-resource/prlist"
+	"github.com/cloudfoundry-community/github-pr-instances-resource/test_helpers"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		description  string
+		version      prlist.Version
+		pullRequests []*models.PullRequest
+		expected     string
+	}{
+		{
+			description: "get writes the branches of each PR",
+			version:     prlist.Version{PRs: "[1,7]"},
+			pullRequests: []*models.PullRequest{
+				test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+				test_helpers.CreateTestPR(7, "refs/heads/develop", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			},
+			expected: `[{"number":1,"base_name":"master","head_name":"pr1"},{"number":7,"base_name":"develop","head_name":"pr7"}]`,
+		},
+		{
+			description: "get writes only the number of PRs that are no longer listed",
+			version:     prlist.Version{PRs: "[1,2]"},
+			pullRequests: []*models.PullRequest{
+				test_helpers.CreateTestPR(1, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen),
+			},
+			expected: `[{"number":1,"base_name":"master","head_name":"pr1"},{"number":2}]`,
+		},
+		{
+			description: "get writes an empty list without looking up PRs",
+			version:     prlist.Version{PRs: "[]"},
+			expected:    `[]`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			github := new(fakes.FakeGithub)
+			github.ListPullRequestsReturns(tc.pullRequests, nil)
+
+			dir := test_helpers.CreateTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			source := prlist.Source{
+				GithubConfig: models.GithubConfig{
+					Repository: "itsdalmo/test-repository",
+				},
+				CommonConfig: models.CommonConfig{
+					AccessToken: "oauthtoken",
+				},
+			}
+			output, err := prlist.Get(prlist.GetRequest{Source: source, Version: tc.version}, github, dir)
+			require.NoError(t, err)
+			assert.Equal(t, tc.version, output.Version)
+			assert.Equal(t, tc.expected, test_helpers.ReadTestFile(t, filepath.Join(dir, "prs.json")))
+			if tc.pullRequests == nil {
+				assert.Equal(t, 0, github.ListPullRequestsCallCount())
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package prlist
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// labelExpression is a boolean expression over the labels of a pull request,
+// e.g. `ready && !(wip || blocked)`.
+type labelExpression interface {
+	eval(labels map[string]bool) bool
+}
+
+type labelName string
+
+func (e labelName) eval(labels map[string]bool) bool { return labels[string(e)] }
+
+type notExpression struct{ expr labelExpression }
+
+func (e notExpression) eval(labels map[string]bool) bool { return !e.expr.eval(labels) }
+
+type andExpression struct{ left, right labelExpression }
+
+func (e andExpression) eval(labels map[string]bool) bool {
+	return e.left.eval(labels) && e.right.eval(labels)
+}
+
+type orExpression struct{ left, right labelExpression }
+
+func (e orExpression) eval(labels map[string]bool) bool {
+	return e.left.eval(labels) || e.right.eval(labels)
+}
+
+type labelToken struct {
+	value string
+	// Label names, as opposed to operators and parentheses.
+	name bool
+	// Position of the token in the expression, starting at 1.
+	pos int
+}
+
+func (t labelToken) String() string {
+	if t.value == "" {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at position %d", t.value, t.pos)
+}
+
+// tokenizeLabelExpression splits an expression into label names, operators and
+// parentheses. Label names that contain spaces or operators can be quoted.
+func tokenizeLabelExpression(s string) ([]labelToken, error) {
+	var tokens []labelToken
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.HasPrefix(s[i:], "&&"), strings.HasPrefix(s[i:], "||"):
+			tokens = append(tokens, labelToken{value: s[i : i+2], pos: i + 1})
+			i += 2
+		case c == '&' || c == '|':
+			return nil, fmt.Errorf("unexpected %q at position %d, use %q", string(c), i+1, strings.Repeat(string(c), 2))
+		case c == '!' || c == '(' || c == ')':
+			tokens = append(tokens, labelToken{value: string(c), pos: i + 1})
+			i++
+		case c == '"':
+			end := strings.IndexByte(s[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("missing closing quote for label name at position %d", i+1)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty label name at position %d", i+1)
+			}
+			tokens = append(tokens, labelToken{value: s[i+1 : i+1+end], name: true, pos: i + 1})
+			i += end + 2
+		default:
+			end := strings.IndexAny(s[i:], " \t&|!()\"")
+			if end < 0 {
+				end = len(s) - i
+			}
+			tokens = append(tokens, labelToken{value: s[i : i+end], name: true, pos: i + 1})
+			i += end
+		}
+	}
+	return tokens, nil
+}
+
+// parseLabelExpression parses a boolean expression over label names, where !
+// takes precedence over &&, which takes precedence over ||.
+func parseLabelExpression(s string) (labelExpression, error) {
+	tokens, err := tokenizeLabelExpression(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("expression is empty")
+	}
+	p := &labelParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.value != "" {
+		return nil, fmt.Errorf("unexpected %s, expected && or ||", t)
+	}
+	return expr, nil
+}
+
+type labelParser struct {
+	tokens []labelToken
+	next   int
+}
+
+func (p *labelParser) peek() labelToken {
+	if p.next < len(p.tokens) {
+		return p.tokens[p.next]
+	}
+	return labelToken{}
+}
+
+func (p *labelParser) parseOr() (labelExpression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); !t.name && t.value == "||"; t = p.peek() {
+		p.next++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpression{left, right}
+	}
+	return left, nil
+}
+
+func (p *labelParser) parseAnd() (labelExpression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); !t.name && t.value == "&&"; t = p.peek() {
+		p.next++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpression{left, right}
+	}
+	return left, nil
+}
+
+func (p *labelParser) parseNot() (labelExpression, error) {
+	t := p.peek()
+	switch {
+	case t.name:
+		p.next++
+		return labelName(t.value), nil
+	case t.value == "!":
+		p.next++
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{expr}, nil
+	case t.value == "(":
+		p.next++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if end := p.peek(); end.name || end.value != ")" {
+			return nil, fmt.Errorf("missing \")\" for %s, got %s", t, end)
+		}
+		p.next++
+		return expr, nil
+	default:
+		return nil, fmt.Errorf("expected a label name, \"!\" or \"(\", got %s", t)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
@@ -14,16 +15,28 @@ import (
 type Source struct {
 	models.CommonConfig
 	models.GithubConfig
-	Paths                   []string                    `json:"paths"`
-	IgnorePaths             []string                    `json:"ignore_paths"`
-	DisableCISkip           bool                        `json:"disable_ci_skip"`
-	DisableForks            bool                        `json:"disable_forks"`
-	IgnoreDrafts            bool                        `json:"ignore_drafts"`
-	BaseBranch              string                      `json:"base_branch"`
-	RequiredReviewApprovals int                         `json:"required_review_approvals"`
-	GitCryptKey             string                      `json:"git_crypt_key"`
-	Labels                  []string                    `json:"labels"`
-	States                  []githubv4.PullRequestState `json:"states"`
+	Paths                   []string                            `json:"paths"`
+	IgnorePaths             []string                            `json:"ignore_paths"`
+	DisableCISkip           bool                                `json:"disable_ci_skip"`
+	DisableForks            bool                                `json:"disable_forks"`
+	IgnoreDrafts            bool                                `json:"ignore_drafts"`
+	BaseBranch              string                              `json:"base_branch"`
+	BaseBranches            []string                            `json:"base_branches"`
+	HeadBranches            []string                            `json:"head_branches"`
+	IgnoreHeadBranches      []string                            `json:"ignore_head_branches"`
+	RequiredReviewApprovals int                                 `json:"required_review_approvals"`
+	GitCryptKey             string                              `json:"git_crypt_key"`
+	Labels                  []string                            `json:"labels"`
+	LabelsMatch             string                              `json:"labels_match"`
+	LabelExpression         string                              `json:"label_expression"`
+	IgnoreLabels            []string                            `json:"ignore_labels"`
+	States                  []githubv4.PullRequestState         `json:"states"`
+	Authors                 []string                            `json:"authors"`
+	IgnoreAuthors           []string                            `json:"ignore_authors"`
+	AuthorAssociations      []githubv4.CommentAuthorAssociation `json:"author_associations"`
+	ForkOwners              []string                            `json:"fork_owners"`
+	RequireOkToTest         bool                                `json:"require_ok_to_test"`
+	OkToTestLabel           string                              `json:"ok_to_test_label"`
 }
 
 // Validate the source configuration.
@@ -40,6 +53,51 @@ func (s *Source) Validate() error {
 	if s.V4Endpoint != "" && s.V3Endpoint == "" {
 		return errors.New("v3_endpoint must be set together with v4_endpoint")
 	}
+	switch strings.ToLower(s.LabelsMatch) {
+	case "", "any", "all":
+	default:
+		return fmt.Errorf("labels_match value \"%s\" must be one of: any, all", s.LabelsMatch)
+	}
+	if s.LabelExpression != "" {
+		if _, err := parseLabelExpression(s.LabelExpression); err != nil {
+			return fmt.Errorf("invalid label_expression: %s", err)
+		}
+	}
+	if s.BaseBranch != "" && len(s.BaseBranches) > 0 {
+		return errors.New("base_branch can not be used with base_branches")
+	}
+	for _, option := range []struct {
+		name     string
+		patterns []string
+	}{
+		{"ignore_labels", s.IgnoreLabels},
+		{"base_branches", s.BaseBranches},
+		{"head_branches", s.HeadBranches},
+		{"ignore_head_branches", s.IgnoreHeadBranches},
+	} {
+		for _, pattern := range option.patterns {
+			if _, err := MatchPattern(pattern, ""); err != nil {
+				return fmt.Errorf("invalid %s pattern %q: %s", option.name, pattern, err)
+			}
+		}
+	}
+	for _, association := range s.AuthorAssociations {
+		switch association {
+		case githubv4.CommentAuthorAssociationOwner:
+		case githubv4.CommentAuthorAssociationMember:
+		case githubv4.CommentAuthorAssociationCollaborator:
+		case githubv4.CommentAuthorAssociationContributor:
+		case githubv4.CommentAuthorAssociationFirstTimeContributor:
+		case githubv4.CommentAuthorAssociationFirstTimer:
+		case githubv4.CommentAuthorAssociationMannequin:
+		case githubv4.CommentAuthorAssociationNone:
+		default:
+			return fmt.Errorf("author_associations value \"%s\" must be one of: OWNER, MEMBER, COLLABORATOR, CONTRIBUTOR, FIRST_TIME_CONTRIBUTOR, FIRST_TIMER, MANNEQUIN, NONE", association)
+		}
+	}
+	if s.DisableForks && len(s.ForkOwners) > 0 {
+		return errors.New("fork_owners can not be used with disable_forks")
+	}
 	for _, state := range s.States {
 		switch state {
 		case githubv4.PullRequestStateOpen:
@@ -52,6 +110,119 @@ func (s *Source) Validate() error {
 	return nil
 }
 
+// states returns the PR states to select, which default to only open PRs.
+func (s *Source) states() []githubv4.PullRequestState {
+	if len(s.States) > 0 {
+		return s.States
+	}
+	return []githubv4.PullRequestState{githubv4.PullRequestStateOpen}
+}
+
+// matchesBaseBranch returns true if the PR targets the base branch (or one of
+// the base branches) specified in source, or if none are specified.
+func (s *Source) matchesBaseBranch(p *models.PullRequest) bool {
+	// Occasionally, github will prefix the baseRefName with
+	// refs/heads/ rather than just using the branch name itself - not
+	// sure when/why this happens.
+	base := strings.TrimPrefix(p.BaseRefName, "refs/heads/")
+	if s.BaseBranch != "" {
+		return base == s.BaseBranch
+	}
+	return len(s.BaseBranches) == 0 || matchesAnyPattern(s.BaseBranches, base)
+}
+
+// matchesHeadBranch returns true if the head branch of the PR matches one of
+// the head_branches specified in source (if any), and none of the
+// ignore_head_branches.
+func (s *Source) matchesHeadBranch(p *models.PullRequest) bool {
+	head := strings.TrimPrefix(p.HeadRefName, "refs/heads/")
+	if len(s.HeadBranches) > 0 && !matchesAnyPattern(s.HeadBranches, head) {
+		return false
+	}
+	return !matchesAnyPattern(s.IgnoreHeadBranches, head)
+}
+
+// matchesAnyPattern returns true if s matches one of the patterns, which are
+// validated along with the source.
+func matchesAnyPattern(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if match, _ := MatchPattern(pattern, s); match {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesLabels returns true if the PR has any (or all, depending on
+// labels_match) of the labels specified in source, or if none are specified.
+func (s *Source) matchesLabels(p *models.PullRequest) bool {
+	if len(s.Labels) == 0 {
+		return true
+	}
+	all := strings.ToLower(s.LabelsMatch) == "all"
+	for _, wanted := range s.Labels {
+		found := false
+		for _, l := range p.Labels {
+			if l.Name == wanted {
+				found = true
+				break
+			}
+		}
+		if found != all {
+			return found
+		}
+	}
+	return all
+}
+
+// okToTestLabel returns the label that approves PRs for testing.
+func (s *Source) okToTestLabel() string {
+	if s.OkToTestLabel != "" {
+		return s.OkToTestLabel
+	}
+	return models.DefaultOkToTestLabel
+}
+
+// matchesAuthor returns true if the PR is allowed by the authors,
+// author_associations and fork_owners specified in source, and its author is
+// not one of the ignore_authors. The allow-lists are a union: if any is
+// specified, the PR must match at least one of them.
+func (s *Source) matchesAuthor(p *models.PullRequest) bool {
+	login := p.AuthorLogin()
+	if containsFold(s.IgnoreAuthors, login) {
+		return false
+	}
+	if len(s.Authors) == 0 && len(s.AuthorAssociations) == 0 && len(s.ForkOwners) == 0 {
+		return true
+	}
+	if containsFold(s.Authors, login) {
+		return true
+	}
+	for _, association := range s.AuthorAssociations {
+		if association == p.AuthorAssociation {
+			return true
+		}
+	}
+	return len(s.ForkOwners) > 0 && s.matchesForkOwner(p)
+}
+
+// matchesForkOwner returns true if the PR is not from a fork, or from a fork
+// owned by one of the fork_owners specified in source.
+func (s *Source) matchesForkOwner(p *models.PullRequest) bool {
+	return !p.IsCrossRepository || containsFold(s.ForkOwners, p.HeadRepository.Owner.Login)
+}
+
+// containsFold returns true if the list contains the login, ignoring case as
+// GitHub does.
+func containsFold(list []string, login string) bool {
+	for _, l := range list {
+		if strings.EqualFold(l, login) {
+			return true
+		}
+	}
+	return false
+}
+
 type Version struct {
 	// JSON encoded list of PR numbers.
 	PRs string `json:"prs"`
@@ -77,5 +248,7 @@ func NewVersion(prs []*models.PullRequest) Version {
 
 // PRData represents a single PR in the get response file.
 type PRData struct {
-	Number int `json:"number"`
+	Number   int    `json:"number"`
+	BaseName string `json:"base_name,omitempty"`
+	HeadName string `json:"head_name,omitempty"`
 }
@@ -0,0 +1,166 @@
+package prlist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+)
+
+func Put(request PutRequest, github models.Github, inputDir string) (*PutResponse, error) {
+	if err := request.Params.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %s", err)
+	}
+
+	// Version available after a GET step.
+	var version Version
+	content, err := ioutil.ReadFile(filepath.Join(inputDir, request.Params.Path, "version.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version from path: %v", err)
+	}
+	if err := json.Unmarshal(content, &version); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal version from file: %v", err)
+	}
+	var listed []int
+	if err := json.Unmarshal([]byte(version.PRs), &listed); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal PRs from version: %v", err)
+	}
+
+	// The version only contains the PR numbers, so look up the current
+	// head of each PR in a single request.
+	pulls, err := github.ListPullRequests(request.Source.states())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last commits: %s", err)
+	}
+	pullsByNumber := make(map[int]*models.PullRequest, len(pulls))
+	for _, p := range pulls {
+		pullsByNumber[p.Number] = p
+	}
+
+	targets := listed
+	if request.Params.CISkipped {
+		isListed := make(map[int]bool, len(listed))
+		for _, n := range listed {
+			isListed[n] = true
+		}
+		filter, err := newPRFilter(request.Source, github)
+		if err != nil {
+			return nil, err
+		}
+		targets = nil
+		for _, p := range pulls {
+			if isListed[p.Number] || !ContainsSkipCI(p.Title) {
+				continue
+			}
+			ok, err := filter.matches(p)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				targets = append(targets, p.Number)
+			}
+		}
+	}
+
+	// A failure for one PR should not prevent updating the others, so the
+	// outcome for each PR is reported in the metadata, and failures are only
+	// reported once all PRs were updated.
+	var metadata models.Metadata
+	var failures []string
+	for _, number := range targets {
+		var err error
+		pull, ok := pullsByNumber[number]
+		if !ok {
+			err = errors.New("pull request is no longer in the selected states")
+		} else {
+			err = putPR(request.Params, github, pull)
+		}
+		result := "success"
+		if err != nil {
+			result = fmt.Sprintf("failed: %s", err)
+			failures = append(failures, fmt.Sprintf("#%d: %s", number, err))
+		}
+		metadata.Add(fmt.Sprintf("pr_%d", number), result)
+	}
+	if len(failures) > 0 && !request.Params.ContinueOnError {
+		return nil, fmt.Errorf("failed to update %d of %d pull requests: %s", len(failures), len(targets), strings.Join(failures, "; "))
+	}
+
+	return &PutResponse{
+		Version:  version,
+		Metadata: metadata,
+	}, nil
+}
+
+func putPR(p PutParameters, github models.Github, pull *models.PullRequest) error {
+	var metadata models.Metadata
+	metadata.Add("pr", strconv.Itoa(pull.Number))
+	metadata.Add("title", pull.Title)
+	metadata.Add("url", pull.URL)
+	metadata.Add("head_name", pull.HeadRefName)
+	metadata.Add("head_sha", pull.Tip.OID)
+	metadata.Add("base_name", pull.BaseRefName)
+
+	// Set status if specified
+	if p.Status != "" {
+		if err := github.UpdateCommitStatus(pull.Tip.OID, p.BaseContext, models.SafeExpandEnv(p.Context), p.Status, models.SafeExpandEnv(p.TargetURL), p.Description); err != nil {
+			return fmt.Errorf("failed to set status: %v", err)
+		}
+	}
+
+	// Set comment if specified
+	if p.Comment != "" {
+		if err := github.PostComment(pull.Number, models.SafeExpandMetadata(p.Comment, metadata)); err != nil {
+			return fmt.Errorf("failed to post comment: %v", err)
+		}
+	}
+
+	// Add labels if specified
+	if len(p.Labels) > 0 {
+		if err := github.AddLabels(pull.Number, p.Labels); err != nil {
+			return fmt.Errorf("failed to add labels: %v", err)
+		}
+	}
+
+	return nil
+}
+
+type PutRequest struct {
+	Source Source        `json:"source"`
+	Params PutParameters `json:"params"`
+}
+
+type PutResponse struct {
+	Version  Version         `json:"version"`
+	Metadata models.Metadata `json:"metadata,omitempty"`
+}
+
+type PutParameters struct {
+	Path            string   `json:"path"`
+	CISkipped       bool     `json:"ci_skipped"`
+	BaseContext     string   `json:"base_context"`
+	Context         string   `json:"context"`
+	TargetURL       string   `json:"target_url"`
+	Description     string   `json:"description"`
+	Status          string   `json:"status"`
+	Comment         string   `json:"comment"`
+	Labels          []string `json:"labels"`
+	ContinueOnError bool     `json:"continue_on_error"`
+}
+
+func (p *PutParameters) Validate() error {
+	if p.Status != "" && !models.IsValidStatus(p.Status) {
+		return fmt.Errorf("unknown status: %s", p.Status)
+	}
+	for _, l := range p.Labels {
+		if strings.TrimSpace(l) == "" {
+			return errors.New("labels must not be empty")
+		}
+	}
+	return nil
+}
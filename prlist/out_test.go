@@ -0,0 +1,191 @@
+package prlist_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models"
+	"github.com/cloudfoundry-community/github-pr-instances-resource/models/fakes"
+	"github.com/cloudfoundry-community/github-pr-instances-resource/prlist"
+	"github.com/cloudfoundry-community/github-pr-instances-resource/test_helpers"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPut(t *testing.T) {
+	skippedPR := test_helpers.CreateTestPR(3, "master", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+	skippedPR.Title = "[ci skip] " + skippedPR.Title
+
+	otherBasePR := test_helpers.CreateTestPR(7, "develop", false, false, 0, nil, false, githubv4.PullRequestStateOpen)
+	otherBasePR.Title = "[ci skip] " + otherBasePR.Title
+
+	skippedForkPR := test_helpers.CreateTestPR(5, "master", false, true, 0, nil, false, githubv4.PullRequestStateOpen)
+	skippedForkPR.Title = "[ci skip] " + skippedForkPR.Title
+
+	skippedLabeledPR := test_helpers.CreateTestPR(6, "master", false, false, 0, []string{"wontfix"}, false, githubv4.PullRequestStateOpen)
+	skippedLabeledPR.Title = "[ci skip] " + skippedLabeledPR.Title
+
+	tests := []struct {
+		description      string
+		source           prlist.Source
+		version          prlist.Version
+		parameters       prlist.PutParameters
+		pullRequests     []*models.PullRequest
+		failingPR        int
+		expectedPRs      []int
+		expectedErr      string
+		expectedMetadata models.Metadata
+	}{
+		{
+			description:  "put with no parameters does nothing",
+			version:      prlist.Version{PRs: "[2,4]"},
+			parameters:   prlist.PutParameters{},
+			pullRequests: testPullRequests,
+			expectedPRs:  []int{},
+			expectedMetadata: models.Metadata{
+				{Name: "pr_2", Value: "success"},
+				{Name: "pr_4", Value: "success"},
+			},
+		},
+
+		{
+			description: "we can set status, comment and labels on all listed PRs",
+			version:     prlist.Version{PRs: "[2,4]"},
+			parameters: prlist.PutParameters{
+				Status:  "success",
+				Context: "pipeline",
+				Comment: "pipeline configured for $head_sha",
+				Labels:  []string{"ci"},
+			},
+			pullRequests: testPullRequests,
+			expectedPRs:  []int{2, 4},
+			expectedMetadata: models.Metadata{
+				{Name: "pr_2", Value: "success"},
+				{Name: "pr_4", Value: "success"},
+			},
+		},
+
+		{
+			description: "we can set status on the PRs skipped by [ci skip] instead",
+			version:     prlist.Version{PRs: "[2,4]"},
+			source: prlist.Source{
+				BaseBranch: "master",
+			},
+			parameters: prlist.PutParameters{
+				CISkipped: true,
+				Status:    "success",
+			},
+			pullRequests: []*models.PullRequest{testPullRequests[0], testPullRequests[1], skippedPR, testPullRequests[3], testPullRequests[4], testPullRequests[5], otherBasePR},
+			expectedPRs:  []int{3},
+			expectedMetadata: models.Metadata{
+				{Name: "pr_3", Value: "success"},
+			},
+		},
+
+		{
+			description: "put only acts on skipped PRs that pass the other filters",
+			version:     prlist.Version{PRs: "[2,4]"},
+			source: prlist.Source{
+				DisableForks: true,
+				IgnoreLabels: []string{"wontfix"},
+			},
+			parameters: prlist.PutParameters{
+				CISkipped: true,
+				Status:    "success",
+			},
+			pullRequests: []*models.PullRequest{testPullRequests[0], testPullRequests[1], skippedPR, testPullRequests[3], skippedForkPR, skippedLabeledPR},
+			expectedPRs:  []int{3},
+			expectedMetadata: models.Metadata{
+				{Name: "pr_3", Value: "success"},
+			},
+		},
+
+		{
+			description: "a failure for one PR is reported without affecting the others",
+			version:     prlist.Version{PRs: "[2,4,13]"},
+			parameters: prlist.PutParameters{
+				Status:          "success",
+				ContinueOnError: true,
+			},
+			pullRequests: testPullRequests,
+			failingPR:    2,
+			expectedPRs:  []int{2, 4},
+			expectedMetadata: models.Metadata{
+				{Name: "pr_2", Value: "failed: failed to set status: boom"},
+				{Name: "pr_4", Value: "success"},
+				{Name: "pr_13", Value: "failed: pull request is no longer in the selected states"},
+			},
+		},
+
+		{
+			description: "put fails after updating the other PRs when one failed",
+			version:     prlist.Version{PRs: "[2,4,13]"},
+			parameters: prlist.PutParameters{
+				Status: "success",
+			},
+			pullRequests: testPullRequests,
+			failingPR:    2,
+			expectedPRs:  []int{2, 4},
+			expectedErr:  "failed to update 2 of 3 pull requests: #2: failed to set status: boom; #13: pull request is no longer in the selected states",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			source := tc.source
+			source.Repository = "itsdalmo/test-repository"
+			source.AccessToken = "oauthtoken"
+
+			github := new(fakes.FakeGithub)
+			github.ListPullRequestsReturns(tc.pullRequests, nil)
+			github.UpdateCommitStatusStub = func(commitRef, _, _, _, _, _ string) error {
+				if tc.failingPR != 0 && commitRef == tc.pullRequests[tc.failingPR-1].Tip.OID {
+					return errors.New("boom")
+				}
+				return nil
+			}
+
+			dir := test_helpers.CreateTestDirectory(t)
+			defer os.RemoveAll(dir)
+
+			// Run get so we have the version for the put request
+			_, err := prlist.Get(prlist.GetRequest{Source: source, Version: tc.version}, github, dir)
+			require.NoError(t, err)
+
+			output, err := prlist.Put(prlist.PutRequest{Source: source, Params: tc.parameters}, github, dir)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tc.version, output.Version)
+				assert.Equal(t, tc.expectedMetadata, output.Metadata)
+			}
+
+			if tc.parameters.Status != "" && assert.Equal(t, len(tc.expectedPRs), github.UpdateCommitStatusCallCount()) {
+				for i, number := range tc.expectedPRs {
+					commit, _, context, status, _, _ := github.UpdateCommitStatusArgsForCall(i)
+					assert.Equal(t, tc.pullRequests[number-1].Tip.OID, commit)
+					assert.Equal(t, tc.parameters.Context, context)
+					assert.Equal(t, tc.parameters.Status, status)
+				}
+			}
+
+			if tc.parameters.Comment != "" && assert.Equal(t, len(tc.expectedPRs), github.PostCommentCallCount()) {
+				for i, number := range tc.expectedPRs {
+					pr, comment := github.PostCommentArgsForCall(i)
+					assert.Equal(t, number, pr)
+					assert.Equal(t, "pipeline configured for "+tc.pullRequests[number-1].Tip.OID, comment)
+				}
+			}
+
+			if len(tc.parameters.Labels) > 0 && assert.Equal(t, len(tc.expectedPRs), github.AddLabelsCallCount()) {
+				for i, number := range tc.expectedPRs {
+					pr, labels := github.AddLabelsArgsForCall(i)
+					assert.Equal(t, number, pr)
+					assert.Equal(t, tc.parameters.Labels, labels)
+				}
+			}
+		})
+	}
+}